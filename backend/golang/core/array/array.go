@@ -5,6 +5,7 @@ package array
 import (
 	"errors"
 	"fmt"
+	"iter"
 	"math/rand/v2"
 	"sort"
 	"strings"
@@ -29,7 +30,7 @@ func Contains[T comparable](s []T, e T) bool {
 func RemoveByValue[T comparable](s []T, value T) []T {
 	for i, v := range s {
 		if v == value {
-			return append(s[:i], s[i+1:]...)
+			return removeAt(s, i)
 		}
 	}
 	return s
@@ -42,7 +43,14 @@ func RemoveByIndex[T any](s []T, index int) ([]T, error) {
 	if index < 0 || index >= len(s) {
 		return nil, errors.New("array: index out of bounds")
 	}
-	return append(s[:index], s[index+1:]...), nil
+	return removeAt(s, index), nil
+}
+
+// removeAt returns a new slice without the element at index i, leaving s untouched.
+func removeAt[T any](s []T, i int) []T {
+	result := make([]T, 0, len(s)-1)
+	result = append(result, s[:i]...)
+	return append(result, s[i+1:]...)
 }
 
 // IndexOf returns the first index of a value in a slice, or -1 if not found.
@@ -56,6 +64,61 @@ func IndexOf[T comparable](s []T, value T) int {
 	return -1
 }
 
+// IndexOfFunc returns the index of the first element satisfying the predicate, or -1 if none does.
+func IndexOfFunc[T any](s []T, pred func(T) bool) int {
+	for i, v := range s {
+		if pred(v) {
+			return i
+		}
+	}
+	return -1
+}
+
+// LastIndexOf returns the last index of a value in a slice, or -1 if not found.
+func LastIndexOf[T comparable](s []T, value T) int {
+	for i := len(s) - 1; i >= 0; i-- {
+		if s[i] == value {
+			return i
+		}
+	}
+	return -1
+}
+
+// BinarySearch searches a slice sorted in ascending order for target.
+// Returns the index of the first match, or the insertion point and false if not found.
+func BinarySearch[T constraints.Ordered](s []T, target T) (int, bool) {
+	return BinarySearchFunc(s, func(v T) int {
+		switch {
+		case v < target:
+			return -1
+		case v > target:
+			return 1
+		}
+		return 0
+	})
+}
+
+// BinarySearchFunc is like BinarySearch but uses cmp, which reports how an element compares
+// to the target: negative if it sorts before, zero if it matches, positive if after.
+func BinarySearchFunc[T any](s []T, cmp func(T) int) (int, bool) {
+	i := sort.Search(len(s), func(i int) bool { return cmp(s[i]) >= 0 })
+	return i, i < len(s) && cmp(s[i]) == 0
+}
+
+// EqualFunc reports whether two slices have the same length and eq holds for every pair
+// of elements at the same index. Unlike AreIdentical, order matters and T need not be comparable.
+func EqualFunc[T any](a, b []T, eq func(x, y T) bool) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if !eq(a[i], b[i]) {
+			return false
+		}
+	}
+	return true
+}
+
 // AreIdentical checks if two slices contain the same elements with identical counts.
 // Works with any comparable type.
 func AreIdentical[T comparable](x, y []T) bool {
@@ -98,6 +161,42 @@ func Map[T, U any](s []T, transform func(T) U) []U {
 	return result
 }
 
+// MapErr is like Map but stops at the first error, returning it wrapped with the element index.
+// No partial result is returned on error.
+func MapErr[T, U any](s []T, transform func(T) (U, error)) ([]U, error) {
+	result := make([]U, len(s))
+	for i, v := range s {
+		u, err := transform(v)
+		if err != nil {
+			return nil, fmt.Errorf("array: map index %d: %w", i, err)
+		}
+		result[i] = u
+	}
+	return result, nil
+}
+
+// FlatMap applies transform to each element and concatenates the results into one slice.
+// transform is called once per element; the results are counted before copying to size the output exactly.
+func FlatMap[T, U any](s []T, transform func(T) []U) []U {
+	return Flatten(Map(s, transform))
+}
+
+// ForEach calls fn for each element with its index.
+func ForEach[T any](s []T, fn func(index int, v T)) {
+	for i, v := range s {
+		fn(i, v)
+	}
+}
+
+// Tap calls fn for each element for its side effects and returns the slice unchanged.
+// Useful for logging or counting in the middle of a Map/Filter chain.
+func Tap[T any](s []T, fn func(T)) []T {
+	for _, v := range s {
+		fn(v)
+	}
+	return s
+}
+
 // Uniq removes duplicates from a slice, preserving order.
 // Works with any comparable type.
 func Uniq[T comparable](s []T) []T {
@@ -112,6 +211,38 @@ func Uniq[T comparable](s []T) []T {
 	return result
 }
 
+// Rotate returns a new slice rotated left by n positions; negative n rotates right.
+// n wraps modulo the length, so Rotate(s, len(s)+1) equals Rotate(s, 1).
+func Rotate[T any](s []T, n int) []T {
+	result := make([]T, 0, len(s))
+	if len(s) == 0 {
+		return result
+	}
+	n %= len(s)
+	if n < 0 {
+		n += len(s)
+	}
+	result = append(result, s[n:]...)
+	return append(result, s[:n]...)
+}
+
+// UniqLast removes duplicates from a slice, keeping the last occurrence of each element.
+// Example: [a b a c] -> [b a c], where Uniq gives [a b c].
+func UniqLast[T comparable](s []T) []T {
+	seen := make(map[T]struct{}, len(s))
+	result := make([]T, 0, len(s))
+	for i := len(s) - 1; i >= 0; i-- {
+		if _, ok := seen[s[i]]; !ok {
+			seen[s[i]] = struct{}{}
+			result = append(result, s[i])
+		}
+	}
+	for i, j := 0, len(result)-1; i < j; i, j = i+1, j-1 {
+		result[i], result[j] = result[j], result[i]
+	}
+	return result
+}
+
 // Reverse returns a new slice with elements in reversed order.
 func Reverse[T any](s []T) []T {
 	result := make([]T, len(s))
@@ -152,6 +283,25 @@ func Sort[T any](s []T, less func(a, b T) bool) []T {
 	return result
 }
 
+// SortStable sorts a copy of the slice using the provided less function,
+// keeping equal elements in their original order.
+func SortStable[T any](s []T, less func(a, b T) bool) []T {
+	result := make([]T, len(s))
+	copy(result, s)
+	sort.SliceStable(result, func(i, j int) bool {
+		return less(result[i], result[j])
+	})
+	return result
+}
+
+// SortByKey sorts a copy of the slice ascending by a key derived from each element.
+// The sort is stable, so elements with equal keys keep their original order.
+func SortByKey[T any, K constraints.Ordered](s []T, key func(T) K) []T {
+	return SortStable(s, func(a, b T) bool {
+		return key(a) < key(b)
+	})
+}
+
 // Concat combines multiple slices into a single slice.
 // Works with any type.
 func Concat[T any](slices ...[]T) []T {
@@ -166,6 +316,12 @@ func Concat[T any](slices ...[]T) []T {
 	return result
 }
 
+// Flatten concatenates a slice of slices into one slice with a single allocation.
+// Returns an empty, non-nil slice for nil or empty input.
+func Flatten[T any](s [][]T) []T {
+	return Concat(s...)
+}
+
 // Find returns the first element in a slice that satisfies the predicate.
 // Returns the element and a boolean indicating if it was found.
 func Find[T any](s []T, match func(T) bool) (T, bool) {
@@ -184,20 +340,55 @@ func Split[T any](s []T, size int) ([][]T, error) {
 	if size <= 0 {
 		return nil, errors.New("array: split size must be positive")
 	}
-	if len(s) == 0 {
-		return [][]T{}, nil
-	}
 	result := make([][]T, 0, (len(s)+size-1)/size)
-	for i := 0; i < len(s); i += size {
-		end := i + size
-		if end > len(s) {
-			end = len(s)
+	for chunk := range Chunks(s, size) {
+		result = append(result, chunk)
+	}
+	return result, nil
+}
+
+// Chunks returns an iterator over consecutive chunks of s of the given size; the last one may be smaller.
+// Chunks are views into s, so mutating them mutates s. Yields nothing if size <= 0.
+func Chunks[T any](s []T, size int) iter.Seq[[]T] {
+	return func(yield func([]T) bool) {
+		if size <= 0 {
+			return
 		}
-		result = append(result, s[i:end])
+		for i := 0; i < len(s); i += size {
+			end := min(i+size, len(s))
+			if !yield(s[i:end]) {
+				return
+			}
+		}
+	}
+}
+
+// Window returns all contiguous sub-slices of the given size, sliding by one element.
+// Returns an empty result if size exceeds the length. Windows are views into s.
+func Window[T any](s []T, size int) ([][]T, error) {
+	if size <= 0 {
+		return nil, errors.New("array: window size must be positive")
+	}
+	if size > len(s) {
+		return [][]T{}, nil
+	}
+	result := make([][]T, 0, len(s)-size+1)
+	for i := 0; i+size <= len(s); i++ {
+		result = append(result, s[i:i+size])
 	}
 	return result, nil
 }
 
+// SlidingReduce applies reducer to every window of the given size (see Window),
+// returning one result per window position, e.g. a moving average or rolling max.
+func SlidingReduce[T, U any](s []T, size int, reducer func([]T) U) ([]U, error) {
+	windows, err := Window(s, size)
+	if err != nil {
+		return nil, err
+	}
+	return Map(windows, reducer), nil
+}
+
 // MinMax finds the minimum and maximum values in a slice.
 // Works with ordered types (int, float64, string, etc.).
 func MinMax[T constraints.Ordered](s []T) (min T, max T, err error) {
@@ -247,6 +438,68 @@ func GroupBy[T any, K comparable](s []T, keyFunc func(T) K) map[K][]T {
 	return result
 }
 
+// ChunkBy splits a slice into runs of consecutive elements sharing the same key,
+// starting a new group whenever the key changes. Unlike GroupBy, equal keys that are
+// not adjacent end up in separate groups. Groups are views into s, so mutating them mutates s.
+func ChunkBy[T any, K comparable](s []T, key func(T) K) [][]T {
+	result := make([][]T, 0)
+	if len(s) == 0 {
+		return result
+	}
+	start, prev := 0, key(s[0])
+	for i := 1; i < len(s); i++ {
+		if k := key(s[i]); k != prev {
+			result = append(result, s[start:i])
+			start, prev = i, k
+		}
+	}
+	return append(result, s[start:])
+}
+
+// ChunkWhile splits a slice into groups, extending the current group with the next element
+// while canExtend returns true for them and starting a new group otherwise.
+// Every group has at least one element. Groups are views into s, so mutating them mutates s.
+// Example: pack items while the total size stays under a budget.
+func ChunkWhile[T any](s []T, canExtend func(group []T, next T) bool) [][]T {
+	result := make([][]T, 0)
+	if len(s) == 0 {
+		return result
+	}
+	start := 0
+	for i := 1; i < len(s); i++ {
+		if !canExtend(s[start:i], s[i]) {
+			result = append(result, s[start:i])
+			start = i
+		}
+	}
+	return append(result, s[start:])
+}
+
+// MergeMaps combines maps into a new one; for duplicate keys the last map wins.
+func MergeMaps[K comparable, V any](maps ...map[K]V) map[K]V {
+	return MergeMapsFunc(func(_ K, _, b V) V { return b }, maps...)
+}
+
+// MergeMapsFunc combines maps into a new one, calling resolve for duplicate keys
+// with the value merged so far and the new one.
+// Example: merging partial GroupBy results with func(_ K, a, b []T) []T { return append(a, b...) }.
+func MergeMapsFunc[K comparable, V any](resolve func(k K, a, b V) V, maps ...map[K]V) map[K]V {
+	size := 0
+	for _, m := range maps {
+		size = max(size, len(m))
+	}
+	result := make(map[K]V, size)
+	for _, m := range maps {
+		for k, v := range m {
+			if existing, ok := result[k]; ok {
+				v = resolve(k, existing, v)
+			}
+			result[k] = v
+		}
+	}
+	return result
+}
+
 // Reduce applies a reduction function over the slice, accumulating a result.
 // Starts with an initial value; T and U can be any types.
 func Reduce[T, U any](s []T, reducer func(U, T) U, initial U) U {
@@ -257,14 +510,50 @@ func Reduce[T, U any](s []T, reducer func(U, T) U, initial U) U {
 	return result
 }
 
+// ReduceWhile is like Reduce but stops as soon as reducer returns false.
+// The accumulator returned with false is the result; remaining elements are not visited.
+func ReduceWhile[T, U any](s []T, reducer func(U, T) (U, bool), initial U) U {
+	result := initial
+	for _, v := range s {
+		var more bool
+		if result, more = reducer(result, v); !more {
+			break
+		}
+	}
+	return result
+}
+
+// Scan is like Reduce but returns every intermediate accumulated value (a running fold).
+// The result has len(s) elements; result[i] is the accumulator after s[i], initial is not included.
+func Scan[T, U any](s []T, fn func(U, T) U, initial U) []U {
+	result := make([]U, len(s))
+	acc := initial
+	for i, v := range s {
+		acc = fn(acc, v)
+		result[i] = acc
+	}
+	return result
+}
+
 // Shuffle randomly reorders a slice.
 // Uses math/rand/v2 for randomization; does not modify the original slice.
 func Shuffle[T any](s []T) []T {
+	return ShuffleRand(nil, s)
+}
+
+// ShuffleRand is like Shuffle but draws from r, so a seeded source gives a deterministic order.
+// A nil r uses the global math/rand/v2 source.
+func ShuffleRand[T any](r *rand.Rand, s []T) []T {
 	result := make([]T, len(s))
 	copy(result, s)
-	rand.Shuffle(len(result), func(i, j int) {
+	swap := func(i, j int) {
 		result[i], result[j] = result[j], result[i]
-	})
+	}
+	if r == nil {
+		rand.Shuffle(len(result), swap)
+	} else {
+		r.Shuffle(len(result), swap)
+	}
 	return result
 }
 
@@ -283,6 +572,34 @@ func Partition[T any](s []T, test func(T) bool) (pass, fail []T) {
 	return pass, fail
 }
 
+// Transpose swaps the rows and columns of a matrix.
+// Ragged rows are padded with zero values up to the longest row if pad is set,
+// otherwise they cause an error.
+func Transpose[T any](matrix [][]T, pad bool) ([][]T, error) {
+	cols := 0
+	for _, row := range matrix {
+		cols = max(cols, len(row))
+	}
+	if !pad {
+		for _, row := range matrix {
+			if len(row) != cols {
+				return nil, errors.New("array: cannot transpose ragged matrix")
+			}
+		}
+	}
+
+	result := make([][]T, cols)
+	for j := range result {
+		result[j] = make([]T, len(matrix))
+		for i, row := range matrix {
+			if j < len(row) {
+				result[j][i] = row[j]
+			}
+		}
+	}
+	return result, nil
+}
+
 // Pair represents a pair of values of two different types.
 type Pair[T, U any] struct {
 	First  T
@@ -300,6 +617,36 @@ func Zip[T, U any](s1 []T, s2 []U) []Pair[T, U] {
 	return result
 }
 
+// RunLengthEncode collapses runs of consecutive equal elements into value and count pairs.
+// Example: [a a b a] -> [{a 2} {b 1} {a 1}].
+func RunLengthEncode[T comparable](s []T) []Pair[T, int] {
+	result := make([]Pair[T, int], 0)
+	for _, v := range s {
+		if n := len(result); n > 0 && result[n-1].First == v {
+			result[n-1].Second++
+			continue
+		}
+		result = append(result, Pair[T, int]{First: v, Second: 1})
+	}
+	return result
+}
+
+// RunLengthDecode expands value and count pairs produced by RunLengthEncode.
+// Pairs with a non-positive count are skipped.
+func RunLengthDecode[T any](pairs []Pair[T, int]) []T {
+	total := 0
+	for _, p := range pairs {
+		total += max(p.Second, 0)
+	}
+	result := make([]T, 0, total)
+	for _, p := range pairs {
+		for i := 0; i < p.Second; i++ {
+			result = append(result, p.First)
+		}
+	}
+	return result
+}
+
 // DistinctCount returns a map of unique elements to their frequencies.
 // Works with any comparable type.
 func DistinctCount[T comparable](s []T) map[T]int {
@@ -310,6 +657,34 @@ func DistinctCount[T comparable](s []T) map[T]int {
 	return counts
 }
 
+// Count returns the number of occurrences of value in a slice.
+func Count[T comparable](s []T, value T) int {
+	return CountFunc(s, func(v T) bool { return v == value })
+}
+
+// CountFunc returns the number of elements that satisfy the predicate.
+func CountFunc[T any](s []T, pred func(T) bool) int {
+	n := 0
+	for _, v := range s {
+		if pred(v) {
+			n++
+		}
+	}
+	return n
+}
+
+// Bucketize counts elements per bucket of their numeric value, like a Prometheus histogram
+// without the cumulative sums. boundaries must be sorted ascending; the result has
+// len(boundaries)+1 counts, where count i covers boundaries[i-1] < v <= boundaries[i]
+// and the last one covers values above the last boundary (and NaN).
+func Bucketize[T any](s []T, boundaries []float64, value func(T) float64) []int {
+	counts := make([]int, len(boundaries)+1)
+	for _, v := range s {
+		counts[sort.SearchFloat64s(boundaries, value(v))]++
+	}
+	return counts
+}
+
 // Take returns the first n elements of a slice.
 // Returns all if n >= len(s), or an empty slice with error if n < 0.
 func Take[T any](s []T, n int) ([]T, error) {
@@ -321,3 +696,123 @@ func Take[T any](s []T, n int) ([]T, error) {
 	}
 	return append([]T{}, s[:n]...), nil
 }
+
+// FirstNonZero returns the first argument that is not the zero value of T, or the zero value if all are.
+// Handy for layering config values: FirstNonZero(flagPort, envPort, defaultPort).
+func FirstNonZero[T comparable](s ...T) T {
+	var zero T
+	for _, v := range s {
+		if v != zero {
+			return v
+		}
+	}
+	return zero
+}
+
+// FirstNonEmpty returns the first non-empty string, or "" if all are empty.
+func FirstNonEmpty[S ~string](s ...S) S {
+	for _, v := range s {
+		if v != "" {
+			return v
+		}
+	}
+	return ""
+}
+
+// FirstNonEmptySlice returns the first slice with a non-zero length, or nil.
+// Unlike a nil check, it treats an empty but non-nil slice as unset.
+func FirstNonEmptySlice[S ~[]E, E any](s ...S) S {
+	for _, v := range s {
+		if len(v) > 0 {
+			return v
+		}
+	}
+	return nil
+}
+
+// Pluck extracts the value at key from each map, as decoded from a JSON array of objects.
+// key may be a dot-separated path into nested objects (e.g. "user.id").
+// Missing or mistyped entries are skipped, or replaced by the zero value if zeroFill is set.
+func Pluck[V any](s []map[string]any, key string, zeroFill bool) []V {
+	path := strings.Split(key, ".")
+	result := make([]V, 0, len(s))
+	for _, m := range s {
+		if v, ok := lookupPath(m, path).(V); ok {
+			result = append(result, v)
+		} else if zeroFill {
+			var zero V
+			result = append(result, zero)
+		}
+	}
+	return result
+}
+
+// lookupPath walks nested maps along path, returning nil if any step is missing.
+func lookupPath(m map[string]any, path []string) any {
+	var cur any = m
+	for _, k := range path {
+		obj, ok := cur.(map[string]any)
+		if !ok {
+			return nil
+		}
+		if cur, ok = obj[k]; !ok {
+			return nil
+		}
+	}
+	return cur
+}
+
+// SymmetricDifference returns the elements that are in exactly one of a and b.
+// Elements of a come first, then those of b, each in original order without duplicates.
+func SymmetricDifference[T comparable](a, b []T) []T {
+	inA := make(map[T]struct{}, len(a))
+	for _, v := range a {
+		inA[v] = struct{}{}
+	}
+	inB := make(map[T]struct{}, len(b))
+	for _, v := range b {
+		inB[v] = struct{}{}
+	}
+
+	result := make([]T, 0)
+	seen := make(map[T]struct{})
+	for _, v := range a {
+		if _, ok := inB[v]; ok {
+			continue
+		}
+		if _, ok := seen[v]; !ok {
+			seen[v] = struct{}{}
+			result = append(result, v)
+		}
+	}
+	for _, v := range b {
+		if _, ok := inA[v]; ok {
+			continue
+		}
+		if _, ok := seen[v]; !ok {
+			seen[v] = struct{}{}
+			result = append(result, v)
+		}
+	}
+	return result
+}
+
+// IsSubset checks if every element of sub is present in super.
+// Duplicates are ignored; an empty sub is a subset of anything.
+func IsSubset[T comparable](sub, super []T) bool {
+	set := make(map[T]struct{}, len(super))
+	for _, v := range super {
+		set[v] = struct{}{}
+	}
+	for _, v := range sub {
+		if _, ok := set[v]; !ok {
+			return false
+		}
+	}
+	return true
+}
+
+// IsSuperset checks if every element of sub is present in super.
+func IsSuperset[T comparable](super, sub []T) bool {
+	return IsSubset(sub, super)
+}
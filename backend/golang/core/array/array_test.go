@@ -0,0 +1,507 @@
+package array
+
+import (
+	"errors"
+	"math"
+	"math/rand/v2"
+	"slices"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestChunksEmpty(t *testing.T) {
+	for chunk := range Chunks([]int{}, 3) {
+		t.Fatalf("unexpected chunk %v", chunk)
+	}
+	for chunk := range Chunks([]int{1, 2}, 0) {
+		t.Fatalf("unexpected chunk %v for non-positive size", chunk)
+	}
+}
+
+func TestChunksSizeLargerThanLen(t *testing.T) {
+	s := []int{1, 2, 3}
+	var got [][]int
+	for chunk := range Chunks(s, 10) {
+		got = append(got, chunk)
+	}
+	if len(got) != 1 || !slices.Equal(got[0], s) {
+		t.Fatalf("got %v, want [%v]", got, s)
+	}
+}
+
+func TestChunksUneven(t *testing.T) {
+	var got [][]int
+	for chunk := range Chunks([]int{1, 2, 3, 4, 5}, 2) {
+		got = append(got, chunk)
+	}
+	want := [][]int{{1, 2}, {3, 4}, {5}}
+	if !slices.EqualFunc(got, want, slices.Equal) {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+}
+
+func TestChunksEarlyBreak(t *testing.T) {
+	var n int
+	for range Chunks([]int{1, 2, 3, 4, 5, 6}, 2) {
+		n++
+		if n == 2 {
+			break
+		}
+	}
+	if n != 2 {
+		t.Fatalf("iterated %d chunks after break, want 2", n)
+	}
+}
+
+func TestChunksAliasOriginal(t *testing.T) {
+	s := []int{1, 2, 3, 4}
+	for chunk := range Chunks(s, 2) {
+		chunk[0] = 0
+	}
+	if want := []int{0, 2, 0, 4}; !slices.Equal(s, want) {
+		t.Fatalf("got %v, want %v", s, want)
+	}
+}
+
+func TestSplit(t *testing.T) {
+	got, err := Split([]int{1, 2, 3}, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := [][]int{{1, 2}, {3}}; !slices.EqualFunc(got, want, slices.Equal) {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+
+	got, err = Split([]int{}, 2)
+	if err != nil || got == nil || len(got) != 0 {
+		t.Fatalf("got %v, %v; want empty non-nil slice", got, err)
+	}
+
+	if _, err := Split([]int{1}, 0); err == nil {
+		t.Fatal("expected error for non-positive size")
+	}
+}
+
+func TestFlatMapSkipsEmpty(t *testing.T) {
+	got := FlatMap([]int{1, 2, 3, 4}, func(v int) []int {
+		if v%2 == 0 {
+			return nil
+		}
+		return []int{v}
+	})
+	if want := []int{1, 3}; !slices.Equal(got, want) {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+}
+
+func TestFlatMapExpands(t *testing.T) {
+	got := FlatMap([]string{"a", "b"}, func(v string) []string {
+		return []string{v, v + v, v + v + v}
+	})
+	want := []string{"a", "aa", "aaa", "b", "bb", "bbb"}
+	if !slices.Equal(got, want) {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+	if cap(got) != len(want) {
+		t.Fatalf("cap = %d, want exact size %d", cap(got), len(want))
+	}
+}
+
+func TestReduceWhileStopsEarly(t *testing.T) {
+	var visited int
+	// Sum sizes until a budget of 10 would be exceeded
+	got := ReduceWhile([]int{4, 3, 2, 5, 1}, func(acc, v int) (int, bool) {
+		visited++
+		if acc+v > 10 {
+			return acc, false
+		}
+		return acc + v, true
+	}, 0)
+	if got != 9 {
+		t.Fatalf("got %d, want 9", got)
+	}
+	if visited != 4 {
+		t.Fatalf("visited %d elements, want 4", visited)
+	}
+}
+
+func TestReduceWhileMatchesReduce(t *testing.T) {
+	s := []string{"a", "b", "c"}
+	concat := func(acc, v string) string { return acc + v }
+	want := Reduce(s, concat, ">")
+	got := ReduceWhile(s, func(acc, v string) (string, bool) { return concat(acc, v), true }, ">")
+	if got != want {
+		t.Fatalf("got %q, want %q", got, want)
+	}
+}
+
+type sortRecord struct {
+	Group int
+	Name  string
+}
+
+func TestSortStableKeepsOrderOfEqualElements(t *testing.T) {
+	in := []sortRecord{{2, "a"}, {1, "b"}, {2, "c"}, {1, "d"}, {2, "e"}}
+	orig := slices.Clone(in)
+	want := []sortRecord{{1, "b"}, {1, "d"}, {2, "a"}, {2, "c"}, {2, "e"}}
+
+	got := SortStable(in, func(a, b sortRecord) bool { return a.Group < b.Group })
+	if !slices.Equal(got, want) {
+		t.Fatalf("SortStable got %v, want %v", got, want)
+	}
+	got = SortByKey(in, func(r sortRecord) int { return r.Group })
+	if !slices.Equal(got, want) {
+		t.Fatalf("SortByKey got %v, want %v", got, want)
+	}
+	if !slices.Equal(in, orig) {
+		t.Fatalf("input mutated: %v", in)
+	}
+}
+
+func TestBinarySearch(t *testing.T) {
+	s := []int{1, 3, 3, 3, 5, 8}
+	tests := []struct {
+		target    int
+		wantIndex int
+		wantFound bool
+	}{
+		{target: 0, wantIndex: 0, wantFound: false},
+		{target: 1, wantIndex: 0, wantFound: true},
+		{target: 3, wantIndex: 1, wantFound: true},
+		{target: 4, wantIndex: 4, wantFound: false},
+		{target: 9, wantIndex: 6, wantFound: false},
+	}
+	for _, tt := range tests {
+		i, found := BinarySearch(s, tt.target)
+		if i != tt.wantIndex || found != tt.wantFound {
+			t.Errorf("BinarySearch(%d) = %d, %v; want %d, %v", tt.target, i, found, tt.wantIndex, tt.wantFound)
+		}
+		si, sfound := slices.BinarySearch(s, tt.target)
+		if i != si || found != sfound {
+			t.Errorf("BinarySearch(%d) = %d, %v; slices.BinarySearch = %d, %v", tt.target, i, found, si, sfound)
+		}
+	}
+
+	if i, found := BinarySearch([]int{}, 1); i != 0 || found {
+		t.Errorf("empty slice: got %d, %v; want 0, false", i, found)
+	}
+}
+
+func TestBinarySearchFunc(t *testing.T) {
+	s := []sortRecord{{1, "a"}, {2, "b"}, {2, "c"}, {4, "d"}}
+	i, found := BinarySearchFunc(s, func(r sortRecord) int { return r.Group - 2 })
+	if i != 1 || !found {
+		t.Fatalf("got %d, %v; want 1, true", i, found)
+	}
+	i, found = BinarySearchFunc(s, func(r sortRecord) int { return r.Group - 3 })
+	if i != 3 || found {
+		t.Fatalf("got %d, %v; want 3, false", i, found)
+	}
+}
+
+func TestRemoveByIndexDoesNotMutateInput(t *testing.T) {
+	s := []int{1, 2, 3, 4}
+	got, err := RemoveByIndex(s, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []int{1, 3, 4}; !slices.Equal(got, want) {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+	if want := []int{1, 2, 3, 4}; !slices.Equal(s, want) {
+		t.Fatalf("original changed to %v, want %v", s, want)
+	}
+}
+
+func TestRemoveByValueDoesNotMutateInput(t *testing.T) {
+	s := []string{"a", "b", "c"}
+	got := RemoveByValue(s, "a")
+	if want := []string{"b", "c"}; !slices.Equal(got, want) {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+	if want := []string{"a", "b", "c"}; !slices.Equal(s, want) {
+		t.Fatalf("original changed to %v, want %v", s, want)
+	}
+}
+
+func TestFlatten(t *testing.T) {
+	got := Flatten([][]int{{1, 2}, {}, nil, {3}})
+	if want := []int{1, 2, 3}; !slices.Equal(got, want) {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+
+	got = Flatten[int](nil)
+	if got == nil || len(got) != 0 {
+		t.Fatalf("got %#v, want empty non-nil slice", got)
+	}
+}
+
+func TestChunkBy(t *testing.T) {
+	tests := []struct {
+		name string
+		in   []string
+		want [][]string
+	}{
+		{
+			name: "adjacent",
+			in:   []string{"a", "a", "b", "b", "b"},
+			want: [][]string{{"a", "a"}, {"b", "b", "b"}},
+		},
+		{
+			name: "non-adjacent",
+			in:   []string{"a", "b", "a", "a"},
+			want: [][]string{{"a"}, {"b"}, {"a", "a"}},
+		},
+		{
+			name: "empty",
+			in:   nil,
+			want: [][]string{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := ChunkBy(tt.in, func(s string) string { return s })
+			if !slices.EqualFunc(got, tt.want, slices.Equal) {
+				t.Fatalf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestChunkWhileBySizeBudget(t *testing.T) {
+	const budget = 10
+	sizes := []int{4, 5, 2, 9, 11, 1, 1}
+	got := ChunkWhile(sizes, func(group []int, next int) bool {
+		total := next
+		for _, v := range group {
+			total += v
+		}
+		return total <= budget
+	})
+	// An item larger than the budget still gets its own group
+	want := [][]int{{4, 5}, {2}, {9}, {11}, {1, 1}}
+	if !slices.EqualFunc(got, want, slices.Equal) {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+
+	if got := ChunkWhile([]int{}, func([]int, int) bool { return true }); len(got) != 0 {
+		t.Fatalf("got %v, want empty", got)
+	}
+}
+
+func TestWindow(t *testing.T) {
+	s := []int{1, 2, 3}
+	tests := []struct {
+		size int
+		want [][]int
+	}{
+		{size: 1, want: [][]int{{1}, {2}, {3}}},
+		{size: 2, want: [][]int{{1, 2}, {2, 3}}},
+		{size: 3, want: [][]int{{1, 2, 3}}},
+		{size: 4, want: [][]int{}},
+	}
+	for _, tt := range tests {
+		got, err := Window(s, tt.size)
+		if err != nil {
+			t.Fatalf("size %d: %v", tt.size, err)
+		}
+		if !slices.EqualFunc(got, tt.want, slices.Equal) {
+			t.Errorf("size %d: got %v, want %v", tt.size, got, tt.want)
+		}
+	}
+
+	if _, err := Window(s, 0); err == nil {
+		t.Fatal("expected error for non-positive size")
+	}
+}
+
+func TestSlidingReduce(t *testing.T) {
+	sum := func(w []int) int { return Reduce(w, func(acc, v int) int { return acc + v }, 0) }
+
+	got, err := SlidingReduce([]int{1, 2, 3, 4, 5}, 3, sum)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []int{6, 9, 12}; !slices.Equal(got, want) {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+
+	got, err = SlidingReduce([]int{1, 2}, 3, sum)
+	if err != nil || len(got) != 0 {
+		t.Fatalf("got %v, %v, want empty result", got, err)
+	}
+
+	if _, err := SlidingReduce([]int{1}, 0, sum); err == nil {
+		t.Fatal("expected error for non-positive size")
+	}
+}
+
+func TestMapErr(t *testing.T) {
+	got, err := MapErr([]string{"1", "2", "3"}, strconv.Atoi)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []int{1, 2, 3}; !slices.Equal(got, want) {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+
+	tests := []struct {
+		name    string
+		in      []string
+		wantErr string
+	}{
+		{name: "first element", in: []string{"x", "2"}, wantErr: "array: map index 0: "},
+		{name: "middle element", in: []string{"1", "x", "3"}, wantErr: "array: map index 1: "},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := MapErr(tt.in, strconv.Atoi)
+			if got != nil {
+				t.Fatalf("got partial result %v", got)
+			}
+			var numErr *strconv.NumError
+			if !errors.As(err, &numErr) {
+				t.Fatalf("error %v does not wrap the transform error", err)
+			}
+			if msg := err.Error(); !strings.HasPrefix(msg, tt.wantErr) {
+				t.Fatalf("error %q, want prefix %q", msg, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestShuffleRandDeterministic(t *testing.T) {
+	in := []int{1, 2, 3, 4, 5, 6, 7, 8}
+	got := ShuffleRand(rand.New(rand.NewPCG(1, 2)), in)
+	if want := []int{3, 2, 6, 8, 4, 7, 5, 1}; !slices.Equal(got, want) {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+	if want := []int{1, 2, 3, 4, 5, 6, 7, 8}; !slices.Equal(in, want) {
+		t.Fatalf("input mutated: %v", in)
+	}
+}
+
+func TestCount(t *testing.T) {
+	s := []string{"a", "b", "a", "c", "a"}
+	tests := []struct {
+		value string
+		want  int
+	}{
+		{value: "a", want: 3},
+		{value: "c", want: 1},
+		{value: "z", want: 0},
+	}
+	for _, tt := range tests {
+		if got := Count(s, tt.value); got != tt.want {
+			t.Errorf("Count(%q) = %d, want %d", tt.value, got, tt.want)
+		}
+	}
+	if got := Count([]int{7, 7, 7}, 7); got != 3 {
+		t.Errorf("all matches: got %d, want 3", got)
+	}
+}
+
+func TestCountFunc(t *testing.T) {
+	s := []int{1, 2, 3, 4, 5}
+	if got := CountFunc(s, func(v int) bool { return v%2 == 0 }); got != 2 {
+		t.Errorf("even: got %d, want 2", got)
+	}
+	if got := CountFunc(s, func(v int) bool { return v > 10 }); got != 0 {
+		t.Errorf("no matches: got %d, want 0", got)
+	}
+	if got := CountFunc(s, func(v int) bool { return v > 0 }); got != len(s) {
+		t.Errorf("all matches: got %d, want %d", got, len(s))
+	}
+}
+
+func TestBucketize(t *testing.T) {
+	latencies := []float64{0.05, 0.1, 0.2, 0.5, 0.7, 1, 3}
+	got := Bucketize(latencies, []float64{0.1, 0.5, 1}, func(v float64) float64 { return v })
+	// Values equal to a boundary fall into the bucket it closes
+	if want := []int{2, 2, 2, 1}; !slices.Equal(got, want) {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+
+	if got := Bucketize([]float64{1, 2}, nil, func(v float64) float64 { return v }); !slices.Equal(got, []int{2}) {
+		t.Fatalf("no boundaries: got %v, want [2]", got)
+	}
+}
+
+func TestUniqLast(t *testing.T) {
+	in := []string{"a", "b", "a", "c"}
+	if got, want := UniqLast(in), []string{"b", "a", "c"}; !slices.Equal(got, want) {
+		t.Fatalf("UniqLast got %v, want %v", got, want)
+	}
+	if got, want := Uniq(in), []string{"a", "b", "c"}; !slices.Equal(got, want) {
+		t.Fatalf("Uniq got %v, want %v", got, want)
+	}
+	if got := UniqLast([]int{}); len(got) != 0 {
+		t.Fatalf("got %v, want empty", got)
+	}
+}
+
+func TestRotate(t *testing.T) {
+	s := []int{1, 2, 3, 4}
+	tests := []struct {
+		n    int
+		want []int
+	}{
+		{n: 0, want: []int{1, 2, 3, 4}},
+		{n: 1, want: []int{2, 3, 4, 1}},
+		{n: 4, want: []int{1, 2, 3, 4}},
+		{n: 6, want: []int{3, 4, 1, 2}},
+		{n: -1, want: []int{4, 1, 2, 3}},
+		{n: -9, want: []int{4, 1, 2, 3}},
+	}
+	for _, tt := range tests {
+		if got := Rotate(s, tt.n); !slices.Equal(got, tt.want) {
+			t.Errorf("Rotate(%d) = %v, want %v", tt.n, got, tt.want)
+		}
+	}
+	if want := []int{1, 2, 3, 4}; !slices.Equal(s, want) {
+		t.Fatalf("input mutated: %v", s)
+	}
+	if got := Rotate([]int{}, 3); len(got) != 0 {
+		t.Fatalf("empty: got %v", got)
+	}
+}
+
+func TestEqualFuncFloatTolerance(t *testing.T) {
+	approx := func(x, y float64) bool { return math.Abs(x-y) < 1e-9 }
+	a := []float64{0.1 + 0.2, 1.0 / 3}
+	b := []float64{0.3, 0.3333333333333333}
+
+	if !EqualFunc(a, b, approx) {
+		t.Fatalf("%v and %v should be equal within tolerance", a, b)
+	}
+	if EqualFunc(a, []float64{0.3, 0.34}, approx) {
+		t.Fatal("values outside tolerance reported equal")
+	}
+	if EqualFunc(a, []float64{1.0 / 3, 0.3}, approx) {
+		t.Fatal("order must matter")
+	}
+	if EqualFunc(a, a[:1], approx) {
+		t.Fatal("length mismatch reported equal")
+	}
+}
+
+func TestIndexOfFunc(t *testing.T) {
+	s := []sortRecord{{1, "a"}, {2, "b"}, {2, "c"}}
+	if got := IndexOfFunc(s, func(r sortRecord) bool { return r.Group == 2 }); got != 1 {
+		t.Errorf("multiple matches: got %d, want 1", got)
+	}
+	if got := IndexOfFunc(s, func(r sortRecord) bool { return r.Group == 9 }); got != -1 {
+		t.Errorf("not found: got %d, want -1", got)
+	}
+}
+
+func TestLastIndexOf(t *testing.T) {
+	s := []string{"a", "b", "a", "c"}
+	if got := LastIndexOf(s, "a"); got != 2 {
+		t.Errorf("multiple matches: got %d, want 2", got)
+	}
+	if got := LastIndexOf(s, "z"); got != -1 {
+		t.Errorf("not found: got %d, want -1", got)
+	}
+}
@@ -0,0 +1,151 @@
+package array
+
+import (
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/RRWM1rr0rB/faraway_lib/backend/golang/core/clock"
+)
+
+// ErrBatcherClosed is returned by Batcher.Add after Close.
+var ErrBatcherClosed = errors.New("array: batcher closed")
+
+// Batcher accumulates items and hands them to a flush callback in batches,
+// whenever size items are pending or every interval, whichever comes first.
+// Safe for concurrent use.
+type Batcher[T any] struct {
+	size  int
+	flush func([]T) error
+
+	mu      sync.Mutex
+	items   []T
+	err     error // Error of the last interval flush, returned by the next call
+	closed  bool
+	stop    func()
+	done    chan struct{} // Closed by Close to stop run
+	stopped chan struct{} // Closed when run has returned
+}
+
+// NewBatcher creates a Batcher flushing batches of up to size items to flush.
+// If interval is positive, pending items are also flushed on every tick of the given
+// clock (system time if nil). Call Close to flush the rest and stop the ticker.
+func NewBatcher[T any](size int, interval time.Duration, c clock.Clock, flush func([]T) error) (*Batcher[T], error) {
+	if size <= 0 {
+		return nil, errors.New("array: batch size must be positive")
+	}
+	if flush == nil {
+		return nil, errors.New("array: batch flush function is nil")
+	}
+
+	b := &Batcher[T]{
+		size:    size,
+		flush:   flush,
+		items:   make([]T, 0, size),
+		done:    make(chan struct{}),
+		stopped: make(chan struct{}),
+	}
+	if interval <= 0 {
+		close(b.stopped)
+		return b, nil
+	}
+
+	if c == nil {
+		c = clock.New()
+	}
+	ticks, stop, err := c.Tick(interval)
+	if err != nil {
+		return nil, err
+	}
+	b.stop = stop
+	go b.run(ticks)
+	return b, nil
+}
+
+// Add appends item to the pending batch and flushes it once it is full.
+// Returns the flush error, or that of an earlier interval flush.
+func (b *Batcher[T]) Add(item T) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.closed {
+		return ErrBatcherClosed
+	}
+	b.items = append(b.items, item)
+	if len(b.items) >= b.size {
+		if err := b.flushLocked(); err != nil {
+			return err
+		}
+	}
+	return b.takeErr()
+}
+
+// Flush hands pending items to the flush callback immediately.
+// Returns the flush error, or that of an earlier interval flush.
+func (b *Batcher[T]) Flush() error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if err := b.flushLocked(); err != nil {
+		return err
+	}
+	return b.takeErr()
+}
+
+// Close stops the interval flushing and flushes pending items.
+// Calling Close more than once is a no-op.
+func (b *Batcher[T]) Close() error {
+	b.mu.Lock()
+	if b.closed {
+		b.mu.Unlock()
+		return nil
+	}
+	b.closed = true
+	b.mu.Unlock()
+
+	// The clock's stop func isn't required to close the tick channel, so stop run directly
+	close(b.done)
+	<-b.stopped
+	if b.stop != nil {
+		b.stop()
+	}
+	return b.Flush()
+}
+
+// run flushes pending items on every tick until Close.
+func (b *Batcher[T]) run(ticks <-chan time.Time) {
+	defer close(b.stopped)
+	for {
+		select {
+		case _, ok := <-ticks:
+			if !ok {
+				return
+			}
+			b.mu.Lock()
+			if err := b.flushLocked(); err != nil {
+				b.err = err
+			}
+			b.mu.Unlock()
+		case <-b.done:
+			return
+		}
+	}
+}
+
+// flushLocked passes pending items to the callback, assumes lock is already held.
+// The lock stays held during the callback so batches are never reordered.
+func (b *Batcher[T]) flushLocked() error {
+	if len(b.items) == 0 {
+		return nil
+	}
+	batch := b.items
+	b.items = make([]T, 0, b.size)
+	return b.flush(batch)
+}
+
+// takeErr returns and clears the error of the last interval flush,
+// assumes lock is already held.
+func (b *Batcher[T]) takeErr() error {
+	err := b.err
+	b.err = nil
+	return err
+}
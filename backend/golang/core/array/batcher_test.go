@@ -0,0 +1,114 @@
+package array
+
+import (
+	"errors"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+)
+
+// manualClock delivers ticks only when tick is called. Its stop func leaves the
+// tick channel open, which Clock.Tick allows.
+type manualClock struct {
+	ch chan time.Time
+}
+
+func newManualClock() *manualClock {
+	return &manualClock{ch: make(chan time.Time)}
+}
+
+func (c *manualClock) tick()                                         { c.ch <- time.Now() }
+func (c *manualClock) After(time.Duration) (<-chan time.Time, error) { return nil, nil }
+func (c *manualClock) Now() time.Time                                { return time.Now() }
+func (c *manualClock) Since(t time.Time) time.Duration               { return time.Since(t) }
+func (c *manualClock) Until(t time.Time) time.Duration               { return time.Until(t) }
+func (c *manualClock) Sleep(time.Duration) error                     { return nil }
+func (c *manualClock) Tick(time.Duration) (<-chan time.Time, func(), error) {
+	return c.ch, func() {}, nil
+}
+
+type batchRecorder struct {
+	mu      sync.Mutex
+	batches [][]int
+	err     error
+}
+
+func (r *batchRecorder) flush(batch []int) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.batches = append(r.batches, batch)
+	return r.err
+}
+
+func (r *batchRecorder) get() [][]int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return slices.Clone(r.batches)
+}
+
+func TestBatcherFlushesOnSize(t *testing.T) {
+	var rec batchRecorder
+	b, err := NewBatcher(2, 0, nil, rec.flush)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := 1; i <= 5; i++ {
+		if err := b.Add(i); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := b.Close(); err != nil {
+		t.Fatal(err)
+	}
+	want := [][]int{{1, 2}, {3, 4}, {5}}
+	if got := rec.get(); !slices.EqualFunc(got, want, slices.Equal) {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+	if err := b.Add(6); !errors.Is(err, ErrBatcherClosed) {
+		t.Fatalf("Add after Close: got %v, want ErrBatcherClosed", err)
+	}
+}
+
+func TestBatcherFlushesOnInterval(t *testing.T) {
+	var rec batchRecorder
+	c := newManualClock()
+	b, err := NewBatcher(10, time.Second, c, rec.flush)
+	if err != nil {
+		t.Fatal(err)
+	}
+	b.Add(1)
+	b.Add(2)
+	c.tick()
+	c.tick() // Second tick is only received after the first flush is done
+	if got, want := rec.get(), [][]int{{1, 2}}; !slices.EqualFunc(got, want, slices.Equal) {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+
+	b.Add(3)
+	if err := b.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if got, want := rec.get(), [][]int{{1, 2}, {3}}; !slices.EqualFunc(got, want, slices.Equal) {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+}
+
+func TestBatcherReportsIntervalFlushError(t *testing.T) {
+	rec := batchRecorder{err: errors.New("insert failed")}
+	c := newManualClock()
+	b, err := NewBatcher(10, time.Second, c, rec.flush)
+	if err != nil {
+		t.Fatal(err)
+	}
+	b.Add(1)
+	c.tick()
+	c.tick()
+	if err := b.Add(2); !errors.Is(err, rec.err) {
+		t.Fatalf("got %v, want %v", err, rec.err)
+	}
+	if err := b.Add(3); err != nil {
+		t.Fatalf("error reported twice: %v", err)
+	}
+	b.Close()
+}
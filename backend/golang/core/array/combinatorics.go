@@ -0,0 +1,140 @@
+package array
+
+// Permutations returns all orderings of s.
+// WARNING: the result has len(s)! elements; use PermutationsFunc for anything but small inputs.
+func Permutations[T any](s []T) [][]T {
+	result := make([][]T, 0)
+	PermutationsFunc(s, func(perm []T) bool {
+		result = append(result, append([]T{}, perm...))
+		return true
+	})
+	return result
+}
+
+// PermutationsFunc calls fn for each ordering of s until fn returns false.
+// The slice passed to fn is reused between calls; copy it to retain it.
+// Uses Heap's algorithm and does not modify s.
+func PermutationsFunc[T any](s []T, fn func(perm []T) bool) {
+	perm := append([]T{}, s...)
+	if !fn(perm) {
+		return
+	}
+
+	c := make([]int, len(perm))
+	for i := 0; i < len(perm); {
+		if c[i] < i {
+			if i%2 == 0 {
+				perm[0], perm[i] = perm[i], perm[0]
+			} else {
+				perm[c[i]], perm[i] = perm[i], perm[c[i]]
+			}
+			if !fn(perm) {
+				return
+			}
+			c[i]++
+			i = 0
+		} else {
+			c[i] = 0
+			i++
+		}
+	}
+}
+
+// Combinations returns all k-element subsets of s, keeping the original element order.
+// Returns an empty result if k < 0 or k > len(s).
+// WARNING: the result has C(len(s), k) elements; use CombinationsFunc for large inputs.
+func Combinations[T any](s []T, k int) [][]T {
+	result := make([][]T, 0)
+	CombinationsFunc(s, k, func(combo []T) bool {
+		result = append(result, append([]T{}, combo...))
+		return true
+	})
+	return result
+}
+
+// CombinationsFunc calls fn for each k-element subset of s until fn returns false.
+// The slice passed to fn is reused between calls; copy it to retain it.
+func CombinationsFunc[T any](s []T, k int, fn func(combo []T) bool) {
+	if k < 0 || k > len(s) {
+		return
+	}
+
+	// idx holds the indexes of the current combination in increasing order
+	idx := make([]int, k)
+	for i := range idx {
+		idx[i] = i
+	}
+	combo := make([]T, k)
+
+	for {
+		for i, j := range idx {
+			combo[i] = s[j]
+		}
+		if !fn(combo) {
+			return
+		}
+
+		// Find the rightmost index that can still move forward
+		i := k - 1
+		for i >= 0 && idx[i] == len(s)-k+i {
+			i--
+		}
+		if i < 0 {
+			return
+		}
+		idx[i]++
+		for j := i + 1; j < k; j++ {
+			idx[j] = idx[j-1] + 1
+		}
+	}
+}
+
+// Product returns the Cartesian product of the input slices,
+// with the last slice varying fastest.
+// Returns an empty result if no slices are given or any of them is empty.
+// WARNING: the result size is the product of all lengths; use ProductFunc for large inputs.
+func Product[T any](slices ...[]T) [][]T {
+	result := make([][]T, 0)
+	ProductFunc(func(tuple []T) bool {
+		result = append(result, append([]T{}, tuple...))
+		return true
+	}, slices...)
+	return result
+}
+
+// ProductFunc calls fn for each tuple of the Cartesian product until fn returns false.
+// The slice passed to fn is reused between calls; copy it to retain it.
+func ProductFunc[T any](fn func(tuple []T) bool, slices ...[]T) {
+	if len(slices) == 0 {
+		return
+	}
+	for _, s := range slices {
+		if len(s) == 0 {
+			return
+		}
+	}
+
+	idx := make([]int, len(slices))
+	tuple := make([]T, len(slices))
+	for {
+		for i, j := range idx {
+			tuple[i] = slices[i][j]
+		}
+		if !fn(tuple) {
+			return
+		}
+
+		// Advance like an odometer, last position first
+		i := len(idx) - 1
+		for ; i >= 0; i-- {
+			idx[i]++
+			if idx[i] < len(slices[i]) {
+				break
+			}
+			idx[i] = 0
+		}
+		if i < 0 {
+			return
+		}
+	}
+}
@@ -0,0 +1,80 @@
+package array
+
+// Iterator lazily produces values one at a time.
+// Combinators such as MapIter and FilterIter wrap it without allocating intermediate slices,
+// so a chained pipeline walks its source once. It is not safe for concurrent use.
+type Iterator[T any] struct {
+	next func() (T, bool)
+}
+
+// NewIterator creates an Iterator from a function returning the next value and
+// false once the sequence is exhausted.
+func NewIterator[T any](next func() (T, bool)) *Iterator[T] {
+	return &Iterator[T]{next: next}
+}
+
+// Iter returns an Iterator over the elements of s.
+func Iter[T any](s []T) *Iterator[T] {
+	i := 0
+	return NewIterator(func() (T, bool) {
+		if i >= len(s) {
+			var zero T
+			return zero, false
+		}
+		v := s[i]
+		i++
+		return v, true
+	})
+}
+
+// Next returns the next value, or false once the iterator is exhausted.
+func (it *Iterator[T]) Next() (T, bool) {
+	return it.next()
+}
+
+// Collect consumes the remaining values into a slice.
+func (it *Iterator[T]) Collect() []T {
+	result := make([]T, 0)
+	for v, ok := it.Next(); ok; v, ok = it.Next() {
+		result = append(result, v)
+	}
+	return result
+}
+
+// MapIter returns an Iterator applying transform to each value of it as it is consumed.
+func MapIter[T, U any](it *Iterator[T], transform func(T) U) *Iterator[U] {
+	return NewIterator(func() (U, bool) {
+		v, ok := it.Next()
+		if !ok {
+			var zero U
+			return zero, false
+		}
+		return transform(v), true
+	})
+}
+
+// FilterIter returns an Iterator yielding only the values of it that satisfy predicate.
+func FilterIter[T any](it *Iterator[T], predicate func(T) bool) *Iterator[T] {
+	return NewIterator(func() (T, bool) {
+		for v, ok := it.Next(); ok; v, ok = it.Next() {
+			if predicate(v) {
+				return v, true
+			}
+		}
+		var zero T
+		return zero, false
+	})
+}
+
+// TakeIter returns an Iterator yielding at most n values of it.
+// it is not advanced past the n-th value.
+func TakeIter[T any](it *Iterator[T], n int) *Iterator[T] {
+	return NewIterator(func() (T, bool) {
+		if n <= 0 {
+			var zero T
+			return zero, false
+		}
+		n--
+		return it.Next()
+	})
+}
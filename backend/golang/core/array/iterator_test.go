@@ -0,0 +1,40 @@
+package array
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestIteratorPipeline(t *testing.T) {
+	var mapped int
+	it := TakeIter(
+		FilterIter(
+			MapIter(Iter([]int{1, 2, 3, 4, 5, 6, 7, 8}), func(v int) int {
+				mapped++
+				return v * v
+			}),
+			func(v int) bool { return v%2 == 0 },
+		),
+		2,
+	)
+	if got, want := it.Collect(), []int{4, 16}; !slices.Equal(got, want) {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+	// The pipeline is lazy: nothing past the second even square is transformed
+	if mapped != 4 {
+		t.Fatalf("transform called %d times, want 4", mapped)
+	}
+}
+
+func TestIteratorExhausted(t *testing.T) {
+	it := Iter([]string{"a"})
+	if v, ok := it.Next(); !ok || v != "a" {
+		t.Fatalf("got %q, %v", v, ok)
+	}
+	if _, ok := it.Next(); ok {
+		t.Fatal("expected exhausted iterator")
+	}
+	if got := it.Collect(); len(got) != 0 {
+		t.Fatalf("got %v, want empty", got)
+	}
+}
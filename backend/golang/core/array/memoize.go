@@ -0,0 +1,72 @@
+package array
+
+import (
+	"container/list"
+	"sync"
+)
+
+// Memoize returns a wrapper around a pure function that caches every result by key.
+// The cache grows without bound; use MemoizeLRU for unbounded key spaces.
+// Safe for concurrent use; concurrent first calls for a key may each run fn.
+func Memoize[K comparable, V any](fn func(K) V) func(K) V {
+	var mu sync.Mutex
+	cache := make(map[K]V)
+	return func(k K) V {
+		mu.Lock()
+		v, ok := cache[k]
+		mu.Unlock()
+		if ok {
+			return v
+		}
+
+		v = fn(k)
+		mu.Lock()
+		cache[k] = v
+		mu.Unlock()
+		return v
+	}
+}
+
+// MemoizeLRU is like Memoize but keeps only the maxSize most recently used results.
+// maxSize <= 0 means no limit.
+func MemoizeLRU[K comparable, V any](fn func(K) V, maxSize int) func(K) V {
+	if maxSize <= 0 {
+		return Memoize(fn)
+	}
+
+	type entry struct {
+		key   K
+		value V
+	}
+	var mu sync.Mutex
+	order := list.New() // Most recently used at the front
+	index := make(map[K]*list.Element, maxSize)
+
+	return func(k K) V {
+		mu.Lock()
+		if el, ok := index[k]; ok {
+			order.MoveToFront(el)
+			v := el.Value.(*entry).value
+			mu.Unlock()
+			return v
+		}
+		mu.Unlock()
+
+		v := fn(k)
+		mu.Lock()
+		defer mu.Unlock()
+		if el, ok := index[k]; ok {
+			// Computed concurrently by another caller
+			el.Value.(*entry).value = v
+			order.MoveToFront(el)
+			return v
+		}
+		index[k] = order.PushFront(&entry{key: k, value: v})
+		if order.Len() > maxSize {
+			oldest := order.Back()
+			order.Remove(oldest)
+			delete(index, oldest.Value.(*entry).key)
+		}
+		return v
+	}
+}
@@ -0,0 +1,41 @@
+package array
+
+import "testing"
+
+func TestMemoize(t *testing.T) {
+	calls := 0
+	square := Memoize(func(v int) int {
+		calls++
+		return v * v
+	})
+	for _, v := range []int{2, 3, 2, 2, 3} {
+		if got := square(v); got != v*v {
+			t.Fatalf("square(%d) = %d", v, got)
+		}
+	}
+	if calls != 2 {
+		t.Fatalf("fn called %d times, want 2", calls)
+	}
+}
+
+func TestMemoizeLRUEvictsLeastRecentlyUsed(t *testing.T) {
+	calls := map[int]int{}
+	ident := MemoizeLRU(func(v int) int {
+		calls[v]++
+		return v
+	}, 2)
+
+	ident(1)
+	ident(2)
+	ident(1) // 2 is now the least recently used
+	ident(3) // Evicts 2
+	ident(1)
+	ident(2)
+
+	if calls[1] != 1 {
+		t.Errorf("key 1 computed %d times, want 1", calls[1])
+	}
+	if calls[2] != 2 {
+		t.Errorf("key 2 computed %d times, want 2 after eviction", calls[2])
+	}
+}
@@ -0,0 +1,53 @@
+package array
+
+import (
+	"context"
+	"fmt"
+	"runtime"
+	"sync/atomic"
+
+	"github.com/RRWM1rr0rB/faraway_lib/backend/golang/core/safe/errorgroup"
+)
+
+// ParallelMap is like MapErr but runs transform on up to workers goroutines
+// (runtime.NumCPU() if workers <= 0), keeping the results in input order.
+// The first error cancels the context passed to the remaining transforms and is returned
+// wrapped with its element index; no partial result is returned on error.
+// Panics in transform are recovered and reported as errors.
+func ParallelMap[T, U any](ctx context.Context, s []T, workers int, transform func(context.Context, T) (U, error)) ([]U, error) {
+	if workers <= 0 {
+		workers = runtime.NumCPU()
+	}
+	workers = min(workers, len(s))
+
+	result := make([]U, len(s))
+	g, _ := errorgroup.WithContext(ctx)
+	var next atomic.Int64 // Index of the next element to transform
+	for range workers {
+		g.Go(func(ctx context.Context) error {
+			for {
+				i := int(next.Add(1) - 1)
+				if i >= len(s) {
+					return nil
+				}
+				if err := ctx.Err(); err != nil {
+					return err
+				}
+				u, err := transform(ctx, s[i])
+				if err != nil {
+					return fmt.Errorf("array: map index %d: %w", i, err)
+				}
+				result[i] = u
+			}
+		})
+	}
+
+	if err := g.Wait(); err != nil {
+		// Report the root cause itself rather than the aggregate, so callers can match it
+		if errs := g.Errors(); len(errs) > 0 {
+			return nil, errs[0]
+		}
+		return nil, err
+	}
+	return result, nil
+}
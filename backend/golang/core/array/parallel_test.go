@@ -0,0 +1,69 @@
+package array
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestParallelMapPreservesOrder(t *testing.T) {
+	in := make([]int, 1000)
+	for i := range in {
+		in[i] = i
+	}
+	got, err := ParallelMap(context.Background(), in, 8, func(_ context.Context, v int) (int, error) {
+		return v * 2, nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := Map(in, func(v int) int { return v * 2 })
+	if !slices.Equal(got, want) {
+		t.Fatal("results out of order")
+	}
+}
+
+func TestParallelMapCancelsOnError(t *testing.T) {
+	errBoom := errors.New("boom")
+	var cancelled atomic.Int32
+	in := make([]int, 64)
+	for i := range in {
+		in[i] = i
+	}
+
+	got, err := ParallelMap(context.Background(), in, 4, func(ctx context.Context, v int) (int, error) {
+		if v == 0 {
+			// Give the other workers time to start their transforms
+			time.Sleep(20 * time.Millisecond)
+			return 0, errBoom
+		}
+		select {
+		case <-ctx.Done():
+			cancelled.Add(1)
+			return 0, ctx.Err()
+		case <-time.After(time.Second):
+			return v, nil
+		}
+	})
+	if !errors.Is(err, errBoom) {
+		t.Fatalf("got error %v, want %v", err, errBoom)
+	}
+	if got != nil {
+		t.Fatalf("got partial result %v", got)
+	}
+	if cancelled.Load() == 0 {
+		t.Fatal("in-flight transforms were not cancelled")
+	}
+}
+
+func TestParallelMapEmpty(t *testing.T) {
+	got, err := ParallelMap(context.Background(), []int{}, 0, func(_ context.Context, v int) (int, error) {
+		return v, nil
+	})
+	if err != nil || len(got) != 0 {
+		t.Fatalf("got %v, %v; want empty result", got, err)
+	}
+}
@@ -0,0 +1,110 @@
+package array
+
+// Set is a collection of unique comparable values that remembers insertion order.
+// The zero value is an empty set ready to use. It is not safe for concurrent use.
+type Set[T comparable] struct {
+	index map[T]int // value -> position in items
+	items []T
+}
+
+// NewSet creates a Set containing the given items.
+func NewSet[T comparable](items ...T) *Set[T] {
+	s := &Set[T]{
+		index: make(map[T]int, len(items)),
+		items: make([]T, 0, len(items)),
+	}
+	s.Add(items...)
+	return s
+}
+
+// Add inserts items that are not already in the set.
+func (s *Set[T]) Add(items ...T) {
+	if s.index == nil {
+		s.index = make(map[T]int, len(items))
+	}
+	for _, v := range items {
+		if _, ok := s.index[v]; ok {
+			continue
+		}
+		s.index[v] = len(s.items)
+		s.items = append(s.items, v)
+	}
+}
+
+// Remove deletes items from the set, preserving the order of the rest.
+// Each removal shifts the later elements, so it costs O(n) in the set's size.
+func (s *Set[T]) Remove(items ...T) {
+	for _, v := range items {
+		i, ok := s.index[v]
+		if !ok {
+			continue
+		}
+		delete(s.index, v)
+		s.items = append(s.items[:i], s.items[i+1:]...)
+		for j := i; j < len(s.items); j++ {
+			s.index[s.items[j]] = j
+		}
+	}
+}
+
+// Has reports whether v is in the set.
+func (s *Set[T]) Has(v T) bool {
+	_, ok := s.index[v]
+	return ok
+}
+
+// Len returns the number of elements in the set.
+func (s *Set[T]) Len() int {
+	return len(s.items)
+}
+
+// Union returns a new set with the elements of s followed by the new elements of other.
+func (s *Set[T]) Union(other *Set[T]) *Set[T] {
+	result := NewSet(s.items...)
+	result.Add(other.items...)
+	return result
+}
+
+// Intersect returns a new set with the elements of s that are also in other.
+func (s *Set[T]) Intersect(other *Set[T]) *Set[T] {
+	result := NewSet[T]()
+	for _, v := range s.items {
+		if other.Has(v) {
+			result.Add(v)
+		}
+	}
+	return result
+}
+
+// Difference returns a new set with the elements of s that are not in other.
+func (s *Set[T]) Difference(other *Set[T]) *Set[T] {
+	result := NewSet[T]()
+	for _, v := range s.items {
+		if !other.Has(v) {
+			result.Add(v)
+		}
+	}
+	return result
+}
+
+// Slice returns the elements in insertion order as a new slice.
+func (s *Set[T]) Slice() []T {
+	return append([]T{}, s.items...)
+}
+
+// Union returns the distinct elements of a followed by those of b not in a.
+func Union[T comparable](a, b []T) []T {
+	s := NewSet(a...)
+	s.Add(b...)
+	return s.items
+}
+
+// Intersect returns the distinct elements of a that are also in b, in the order of a.
+func Intersect[T comparable](a, b []T) []T {
+	return NewSet(a...).Intersect(NewSet(b...)).items
+}
+
+// Difference returns the distinct elements of a that are not in b, in the order of a.
+func Difference[T comparable](a, b []T) []T {
+	return NewSet(a...).Difference(NewSet(b...)).items
+}
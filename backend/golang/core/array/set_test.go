@@ -0,0 +1,106 @@
+package array
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestSliceSetOperations(t *testing.T) {
+	tests := []struct {
+		name      string
+		a, b      []int
+		union     []int
+		intersect []int
+		diff      []int
+	}{
+		{
+			name:      "disjoint",
+			a:         []int{1, 2},
+			b:         []int{3, 4},
+			union:     []int{1, 2, 3, 4},
+			intersect: []int{},
+			diff:      []int{1, 2},
+		},
+		{
+			name:      "full overlap",
+			a:         []int{3, 1, 2},
+			b:         []int{1, 2, 3},
+			union:     []int{3, 1, 2},
+			intersect: []int{3, 1, 2},
+			diff:      []int{},
+		},
+		{
+			name:      "duplicates",
+			a:         []int{2, 1, 2, 3, 1},
+			b:         []int{3, 3, 4, 4},
+			union:     []int{2, 1, 3, 4},
+			intersect: []int{3},
+			diff:      []int{2, 1},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Union(tt.a, tt.b); !slices.Equal(got, tt.union) {
+				t.Errorf("Union = %v, want %v", got, tt.union)
+			}
+			if got := Intersect(tt.a, tt.b); !slices.Equal(got, tt.intersect) {
+				t.Errorf("Intersect = %v, want %v", got, tt.intersect)
+			}
+			if got := Difference(tt.a, tt.b); !slices.Equal(got, tt.diff) {
+				t.Errorf("Difference = %v, want %v", got, tt.diff)
+			}
+		})
+	}
+}
+
+func TestSet(t *testing.T) {
+	var s Set[string] // the zero value is usable
+	s.Add("b", "a", "b", "c")
+	if got, want := s.Slice(), []string{"b", "a", "c"}; !slices.Equal(got, want) {
+		t.Fatalf("Slice() = %v, want %v", got, want)
+	}
+	if !s.Has("a") || s.Has("d") || s.Len() != 3 {
+		t.Fatalf("Has(a) = %v, Has(d) = %v, Len() = %d", s.Has("a"), s.Has("d"), s.Len())
+	}
+
+	s.Remove("b", "d")
+	if got, want := s.Slice(), []string{"a", "c"}; !slices.Equal(got, want) {
+		t.Fatalf("after Remove: Slice() = %v, want %v", got, want)
+	}
+	s.Add("b")
+	if got, want := s.Slice(), []string{"a", "c", "b"}; !slices.Equal(got, want) {
+		t.Fatalf("after re-Add: Slice() = %v, want %v", got, want)
+	}
+	if s.Has("d") || !s.Has("b") {
+		t.Fatalf("index out of sync after Remove: Has(d) = %v, Has(b) = %v", s.Has("d"), s.Has("b"))
+	}
+}
+
+func TestSetOperations(t *testing.T) {
+	a := NewSet(3, 1, 2, 5)
+	b := NewSet(2, 4, 3)
+	var empty Set[int]
+
+	tests := []struct {
+		name string
+		got  *Set[int]
+		want []int
+	}{
+		{"union", a.Union(b), []int{3, 1, 2, 5, 4}},
+		{"intersect", a.Intersect(b), []int{3, 2}},
+		{"difference", a.Difference(b), []int{1, 5}},
+		{"union empty", a.Union(&empty), []int{3, 1, 2, 5}},
+		{"intersect empty", a.Intersect(&empty), []int{}},
+		{"difference from empty", empty.Difference(a), []int{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.got.Slice(); !slices.Equal(got, tt.want) {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+	if got, want := a.Slice(), []int{3, 1, 2, 5}; !slices.Equal(got, want) {
+		t.Errorf("operations modified the receiver: %v, want %v", got, want)
+	}
+}
@@ -6,12 +6,27 @@ package bytes
 import (
 	"bytes"
 	"compress/gzip"
+	"compress/zlib"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding"
 	"encoding/base64"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
+	"reflect"
+	"runtime"
+	"sort"
 	"strings"
 	"sync"
+	"unicode/utf8"
+	"unsafe"
+
+	"github.com/RRWM1rr0rB/faraway_lib/backend/golang/core/array"
+	"github.com/klauspost/compress/zstd"
 )
 
 // bufferPool maintains a pool of reusable bytes.Buffer objects to reduce allocations
@@ -21,6 +36,31 @@ var bufferPool = sync.Pool{
 	},
 }
 
+// GetBuffer returns an empty buffer from the package pool.
+// Returns:
+//   - *bytes.Buffer: Buffer ready for writing
+//
+// Note:
+//   - Return it with PutBuffer once its contents are no longer referenced
+func GetBuffer() *bytes.Buffer {
+	buf := bufferPool.Get().(*bytes.Buffer)
+	buf.Reset()
+	return buf
+}
+
+// PutBuffer resets b and returns it to the package pool.
+// b: Buffer obtained from GetBuffer; nil is ignored
+//
+// Note:
+//   - b and any slice from b.Bytes() must not be used after the call
+func PutBuffer(b *bytes.Buffer) {
+	if b == nil {
+		return
+	}
+	b.Reset()
+	bufferPool.Put(b)
+}
+
 // Serialize converts any value to JSON-encoded []byte.
 // T: The type of the value to serialize
 // v: The value to serialize
@@ -63,6 +103,148 @@ func Deserialize[T any](data []byte) (T, error) {
 	return result, nil
 }
 
+// streamFlushSize is how much encoded data SerializeTo buffers before writing it out
+const streamFlushSize = 32 * 1024
+
+// SerializeTo writes v to w as JSON followed by a newline, like json.Encoder.
+// T: The type of the value to serialize
+// w: Destination for the encoded data
+// v: The value to serialize
+// Returns:
+//   - error: Marshaling or write error if any
+//
+// Notes:
+//   - Slices are encoded one element at a time through a pooled buffer, so memory use
+//     is bounded by the largest element rather than the whole payload
+func SerializeTo[T any](w io.Writer, v T) error {
+	rv := reflect.ValueOf(v)
+	if !streamable(reflect.TypeFor[T]()) || rv.IsNil() {
+		if err := json.NewEncoder(w).Encode(v); err != nil {
+			return fmt.Errorf("serialize: encoding error: %w", err)
+		}
+		return nil
+	}
+
+	buf := GetBuffer()
+	defer PutBuffer(buf)
+
+	enc := json.NewEncoder(buf)
+	buf.WriteByte('[')
+	for i := 0; i < rv.Len(); i++ {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		if err := enc.Encode(rv.Index(i).Interface()); err != nil {
+			return fmt.Errorf("serialize: marshaling error at index %d: %w", i, err)
+		}
+		buf.Truncate(buf.Len() - 1) // Drop the newline Encode appends
+		if buf.Len() >= streamFlushSize {
+			if _, err := buf.WriteTo(w); err != nil {
+				return fmt.Errorf("serialize: write error: %w", err)
+			}
+		}
+	}
+	buf.WriteString("]\n")
+	if _, err := buf.WriteTo(w); err != nil {
+		return fmt.Errorf("serialize: write error: %w", err)
+	}
+	return nil
+}
+
+// DeserializeFrom reads one JSON value of the specified type from r.
+// T: The target type for deserialization
+// r: Source of the encoded data
+// Returns:
+//   - T: Deserialized value
+//   - error: Unmarshaling or read error if any
+//
+// Notes:
+//   - Slices are decoded one element at a time, so only the result is held in memory,
+//     not the raw JSON as well
+//   - r may be read past the end of the value due to decoder buffering
+func DeserializeFrom[T any](r io.Reader) (T, error) {
+	var result T
+	dec := json.NewDecoder(r)
+
+	t := reflect.TypeFor[T]()
+	if !streamable(t) {
+		if err := dec.Decode(&result); err != nil {
+			return result, fmt.Errorf("deserialize: decoding error: %w", err)
+		}
+		return result, nil
+	}
+
+	tok, err := dec.Token()
+	if err != nil {
+		return result, fmt.Errorf("deserialize: decoding error: %w", err)
+	}
+	if tok == nil {
+		return result, nil // JSON null leaves the slice nil
+	}
+	if delim, ok := tok.(json.Delim); !ok || delim != '[' {
+		return result, fmt.Errorf("deserialize: expected JSON array, got %v", tok)
+	}
+
+	rv := reflect.MakeSlice(t, 0, 0)
+	for i := 0; dec.More(); i++ {
+		elem := reflect.New(t.Elem())
+		if err := dec.Decode(elem.Interface()); err != nil {
+			return result, fmt.Errorf("deserialize: unmarshaling error at index %d: %w", i, err)
+		}
+		rv = reflect.Append(rv, elem.Elem())
+	}
+	if _, err := dec.Token(); err != nil {
+		return result, fmt.Errorf("deserialize: decoding error: %w", err)
+	}
+	return rv.Interface().(T), nil
+}
+
+// streamable reports whether t is a slice that can be encoded element by element
+func streamable(t reflect.Type) bool {
+	if t.Kind() != reflect.Slice || t.Elem().Kind() == reflect.Uint8 {
+		return false // []byte is encoded as a single base64 string
+	}
+	// Custom (un)marshalers decide the encoding of the whole value
+	for _, iface := range []reflect.Type{
+		reflect.TypeFor[json.Marshaler](),
+		reflect.TypeFor[json.Unmarshaler](),
+		reflect.TypeFor[encoding.TextMarshaler](),
+		reflect.TypeFor[encoding.TextUnmarshaler](),
+	} {
+		if t.Implements(iface) || reflect.PointerTo(t).Implements(iface) {
+			return false
+		}
+	}
+	return true
+}
+
+// Algorithm selects the compression format used by CompressAlgo and DecompressAlgo
+type Algorithm int
+
+// Compression algorithms
+const (
+	GZIP Algorithm = iota // gzip (RFC 1952), the default of Compress
+	ZLIB                  // zlib (RFC 1950)
+	ZSTD                  // Zstandard (RFC 8878)
+)
+
+// String returns the algorithm name
+func (a Algorithm) String() string {
+	switch a {
+	case GZIP:
+		return "gzip"
+	case ZLIB:
+		return "zlib"
+	case ZSTD:
+		return "zstd"
+	default:
+		return fmt.Sprintf("Algorithm(%d)", int(a))
+	}
+}
+
+// zstdDecoder is shared by all zstd decompressions, DecodeAll is safe for concurrent use
+var zstdDecoder, _ = zstd.NewReader(nil, zstd.WithDecoderConcurrency(0))
+
 // Compress data using gzip with specified compression level.
 // data: Input data to compress
 // level: Compression level (gzip.BestSpeed, gzip.BestCompression, etc.)
@@ -73,24 +255,56 @@ func Deserialize[T any](data []byte) (T, error) {
 // Notes:
 //   - Uses buffer pooling for efficient memory reuse
 func Compress(data []byte, level int) ([]byte, error) {
-	buf := bufferPool.Get().(*bytes.Buffer)
-	defer bufferPool.Put(buf)
-	buf.Reset()
+	return CompressAlgo(data, GZIP, level)
+}
 
-	gz, err := gzip.NewWriterLevel(buf, level)
+// CompressAlgo compresses data with the given algorithm and level.
+// data: Input data to compress
+// algo: Compression algorithm (GZIP, ZLIB or ZSTD)
+// level: Compression level; gzip/zlib levels for GZIP and ZLIB, zstd levels (1-22) for ZSTD,
+// 0 or less selects the algorithm default
+// Returns:
+//   - []byte: Compressed data
+//   - error: Compression-related error if any
+//
+// Notes:
+//   - Uses buffer pooling for efficient memory reuse
+func CompressAlgo(data []byte, algo Algorithm, level int) ([]byte, error) {
+	buf := GetBuffer()
+	defer PutBuffer(buf)
+
+	var (
+		w   io.WriteCloser
+		err error
+	)
+	switch algo {
+	case GZIP:
+		w, err = gzip.NewWriterLevel(buf, level)
+	case ZLIB:
+		w, err = zlib.NewWriterLevel(buf, level)
+	case ZSTD:
+		zstdLevel := zstd.SpeedDefault
+		if level > 0 {
+			zstdLevel = zstd.EncoderLevelFromZstd(level)
+		}
+		w, err = zstd.NewWriter(buf, zstd.WithEncoderLevel(zstdLevel), zstd.WithEncoderConcurrency(1))
+	default:
+		return nil, fmt.Errorf("compress: unknown algorithm %v", algo)
+	}
 	if err != nil {
 		return nil, fmt.Errorf("compress: writer initialization failed: %w", err)
 	}
 
-	if _, err := gz.Write(data); err != nil {
+	if _, err := w.Write(data); err != nil {
 		return nil, fmt.Errorf("compress: data write failed: %w", err)
 	}
 
-	if err := gz.Close(); err != nil {
+	if err := w.Close(); err != nil {
 		return nil, fmt.Errorf("compress: writer close failed: %w", err)
 	}
 
-	return buf.Bytes(), nil
+	// Copy out, the buffer goes back to the pool
+	return append([]byte(nil), buf.Bytes()...), nil
 }
 
 // MustCompress compresses data using gzip and panics on error.
@@ -106,16 +320,46 @@ func MustCompress(data []byte, level int) []byte {
 	return compressed
 }
 
-// Decompress gzip-compressed data.
+// Decompress compressed data, detecting the algorithm from its magic bytes.
+// data: Compressed input data (gzip, zlib or zstd)
+// Returns:
+//   - []byte: Decompressed data
+//   - error: Decompression-related error if any
+//
+// Notes:
+//   - Data with no recognized header is treated as gzip, as before zlib and zstd support
+func Decompress(data []byte) ([]byte, error) {
+	return DecompressAlgo(data, DetectAlgorithm(data))
+}
+
+// DecompressAlgo decompresses data compressed with the given algorithm.
 // data: Compressed input data
+// algo: Compression algorithm (GZIP, ZLIB or ZSTD)
 // Returns:
 //   - []byte: Decompressed data
 //   - error: Decompression-related error if any
 //
 // Notes:
 //   - Performs integrity check for trailing data
-func Decompress(data []byte) ([]byte, error) {
-	r, err := gzip.NewReader(bytes.NewReader(data))
+func DecompressAlgo(data []byte, algo Algorithm) ([]byte, error) {
+	var (
+		r   io.ReadCloser
+		err error
+	)
+	switch algo {
+	case GZIP:
+		r, err = gzip.NewReader(bytes.NewReader(data))
+	case ZLIB:
+		r, err = zlib.NewReader(bytes.NewReader(data))
+	case ZSTD:
+		result, err := zstdDecoder.DecodeAll(data, nil)
+		if err != nil {
+			return nil, fmt.Errorf("decompress: data read failed: %w", err)
+		}
+		return result, nil
+	default:
+		return nil, fmt.Errorf("decompress: unknown algorithm %v", algo)
+	}
 	if err != nil {
 		return nil, fmt.Errorf("decompress: reader initialization failed: %w", err)
 	}
@@ -128,12 +372,30 @@ func Decompress(data []byte) ([]byte, error) {
 
 	// Check for trailing data which might indicate corruption
 	if extra, err := io.ReadAll(r); err != nil || len(extra) > 0 {
-		return result, fmt.Errorf("decompress: corrupted gzip data")
+		return result, fmt.Errorf("decompress: corrupted %v data", algo)
 	}
 
 	return result, nil
 }
 
+// DetectAlgorithm identifies the compression format from the leading magic bytes.
+// data: Compressed input data
+// Returns:
+//   - Algorithm: Detected algorithm, GZIP if the header is not recognized
+func DetectAlgorithm(data []byte) Algorithm {
+	switch {
+	case len(data) >= 4 && data[0] == 0x28 && data[1] == 0xb5 && data[2] == 0x2f && data[3] == 0xfd:
+		return ZSTD
+	case len(data) >= 2 && data[0] == 0x1f && data[1] == 0x8b:
+		return GZIP
+	case len(data) >= 2 && data[0]&0x0f == 8 && (uint16(data[0])<<8|uint16(data[1]))%31 == 0:
+		// Deflate method with a valid header checksum
+		return ZLIB
+	default:
+		return GZIP
+	}
+}
+
 // MustDecompress decompresses data and panics on error.
 // data: Compressed input data
 // Returns:
@@ -181,6 +443,35 @@ func FromBase64(s string, encType int) ([]byte, error) {
 	}
 }
 
+// NewBase64Encoder returns a writer that base64-encodes data into w as it streams.
+// w: Destination for the encoded output
+// encType: Encoding type (URLEncoding or StdEncoding)
+// Returns:
+//   - io.WriteCloser: Encoding writer
+//
+// Note:
+//   - Close must be called to flush the final partial block; it does not close w
+func NewBase64Encoder(w io.Writer, encType int) io.WriteCloser {
+	return base64.NewEncoder(base64Encoding(encType), w)
+}
+
+// NewBase64Decoder returns a reader that decodes base64 data from r as it streams.
+// r: Source of the encoded input
+// encType: Encoding type (URLEncoding or StdEncoding)
+// Returns:
+//   - io.Reader: Decoding reader
+func NewBase64Decoder(r io.Reader, encType int) io.Reader {
+	return base64.NewDecoder(base64Encoding(encType), r)
+}
+
+// base64Encoding maps an encoding type to the base64 encoding
+func base64Encoding(encType int) *base64.Encoding {
+	if encType == URLEncoding {
+		return base64.URLEncoding
+	}
+	return base64.StdEncoding
+}
+
 // HexDump generates formatted hexadecimal representation of data.
 // data: Input data to format
 // bytesPerLine: Number of bytes per line in output
@@ -204,14 +495,22 @@ func HexDump(data []byte, bytesPerLine int) string {
 	return strings.TrimSpace(sb.String())
 }
 
+// maxDumpDepth caps how deep Dump descends into nested values
+const maxDumpDepth = 32
+
 // Dump generates structured string representation of complex data types.
-// data: Value to dump (supports maps, slices, and primitives)
+// data: Value to dump (supports structs, pointers, maps, slices, arrays and primitives)
 // indent: Indentation string (e.g., "  " for two spaces)
 // Returns:
 //   - string: Formatted dump output
+//
+// Notes:
+//   - Only exported struct fields are shown; structs implementing fmt.Stringer use String
+//   - Map keys are sorted for stable output
+//   - Cyclic pointers are printed as "<cycle>" and nesting stops at 32 levels
 func Dump(data interface{}, indent string) string {
 	var sb strings.Builder
-	dumpValue(&sb, data, indent, 0)
+	dumpValue(&sb, reflect.ValueOf(data), indent, 0, make(map[uintptr]struct{}))
 	return sb.String()
 }
 
@@ -220,66 +519,212 @@ func Dump(data interface{}, indent string) string {
 // v: Current value to format
 // indent: Indentation string
 // depth: Current recursion depth
-func dumpValue(sb *strings.Builder, v interface{}, indent string, depth int) {
+// visited: Addresses of pointers and maps on the current path, for cycle detection
+func dumpValue(sb *strings.Builder, v reflect.Value, indent string, depth int, visited map[uintptr]struct{}) {
 	const indentStep = 2
 	prefix := strings.Repeat(indent, depth)
 
-	switch val := v.(type) {
-	case map[string]interface{}:
+	if !v.IsValid() {
+		sb.WriteString("<nil>")
+		return
+	}
+	if depth > maxDumpDepth {
+		sb.WriteString("<max depth>")
+		return
+	}
+
+	switch v.Kind() {
+	case reflect.Interface:
+		dumpValue(sb, v.Elem(), indent, depth, visited)
+	case reflect.Pointer:
+		if v.IsNil() {
+			sb.WriteString("nil")
+			return
+		}
+		addr := v.Pointer()
+		if _, ok := visited[addr]; ok {
+			sb.WriteString("<cycle>")
+			return
+		}
+		visited[addr] = struct{}{}
+		defer delete(visited, addr)
+		sb.WriteByte('&')
+		dumpValue(sb, v.Elem(), indent, depth, visited)
+	case reflect.Struct:
+		if s, ok := v.Interface().(fmt.Stringer); ok {
+			fmt.Fprintf(sb, "%q", s.String())
+			return
+		}
+		sb.WriteString(v.Type().Name() + "{\n")
+		for i := 0; i < v.NumField(); i++ {
+			field := v.Type().Field(i)
+			if !field.IsExported() {
+				continue
+			}
+			fmt.Fprintf(sb, "%s%*s%s: ", prefix, indentStep, "", field.Name)
+			dumpValue(sb, v.Field(i), indent, depth+1, visited)
+			sb.WriteString("\n")
+		}
+		fmt.Fprintf(sb, "%s}", prefix)
+	case reflect.Map:
+		if v.IsNil() {
+			sb.WriteString("nil")
+			return
+		}
+		addr := v.Pointer()
+		if _, ok := visited[addr]; ok {
+			sb.WriteString("<cycle>")
+			return
+		}
+		visited[addr] = struct{}{}
+		defer delete(visited, addr)
+
+		keys := v.MapKeys()
+		sort.Slice(keys, func(i, j int) bool {
+			return fmt.Sprint(keys[i].Interface()) < fmt.Sprint(keys[j].Interface())
+		})
 		sb.WriteString("{\n")
-		for k, v := range val {
-			fmt.Fprintf(sb, "%s%*s%s: ", prefix, indentStep, "", k)
-			dumpValue(sb, v, indent, depth+1)
+		for _, k := range keys {
+			fmt.Fprintf(sb, "%s%*s%v: ", prefix, indentStep, "", k.Interface())
+			dumpValue(sb, v.MapIndex(k), indent, depth+1, visited)
 			sb.WriteString("\n")
 		}
 		fmt.Fprintf(sb, "%s}", prefix)
-	case []interface{}:
+	case reflect.Slice, reflect.Array:
+		if v.Kind() == reflect.Slice && v.IsNil() {
+			sb.WriteString("nil")
+			return
+		}
+		if v.Type().Elem().Kind() == reflect.Uint8 {
+			fmt.Fprintf(sb, "%#v", v.Interface()) // Bytes read better on one line
+			return
+		}
 		sb.WriteString("[\n")
-		for _, item := range val {
+		for i := 0; i < v.Len(); i++ {
 			fmt.Fprintf(sb, "%s%*s", prefix, indentStep, "")
-			dumpValue(sb, item, indent, depth+1)
+			dumpValue(sb, v.Index(i), indent, depth+1, visited)
 			sb.WriteString("\n")
 		}
 		fmt.Fprintf(sb, "%s]", prefix)
 	default:
-		fmt.Fprintf(sb, "%#v", val)
+		if !v.CanInterface() {
+			sb.WriteString("<unexported>")
+			return
+		}
+		fmt.Fprintf(sb, "%#v", v.Interface())
 	}
 }
 
-// String converts []byte to string without allocation.
+// String converts []byte to string.
 // data: Input byte slice
 // Returns:
 //   - string: Converted string
 //
 // Note:
-//   - Unsafe if original byte slice is modified
+//   - Copies data, so later changes to the slice do not affect the string;
+//     see UnsafeString for a zero-copy conversion
 func String(data []byte) string {
 	return string(data)
 }
 
-// Bytes converts string to []byte without allocation.
+// Bytes converts string to []byte.
 // s: Input string
 // Returns:
 //   - []byte: Converted byte slice
 //
 // Note:
-//   - Unsafe if original string is modified
+//   - Copies s, so the result may be modified freely; see UnsafeBytes for a zero-copy conversion
 func Bytes(s string) []byte {
 	return []byte(s)
 }
 
-// Equal performs constant-time comparison of byte slices.
+// UnsafeString converts []byte to string without copying.
+// data: Input byte slice
+// Returns:
+//   - string: String sharing memory with data
+//
+// Note:
+//   - The string aliases data: modifying data afterwards changes the string, breaking
+//     the immutability the rest of Go assumes (map keys, interned values, etc.)
+//   - Only use it when data is never written again for the lifetime of the string
+func UnsafeString(data []byte) string {
+	if len(data) == 0 {
+		return ""
+	}
+	return unsafe.String(unsafe.SliceData(data), len(data))
+}
+
+// UnsafeBytes converts string to []byte without copying.
+// s: Input string
+// Returns:
+//   - []byte: Slice sharing memory with s
+//
+// Note:
+//   - The slice must never be written to: string memory may be read-only,
+//     and writing to it can crash the program or corrupt other strings
+//   - Returns nil for an empty string
+func UnsafeBytes(s string) []byte {
+	if s == "" {
+		return nil
+	}
+	return unsafe.Slice(unsafe.StringData(s), len(s))
+}
+
+// Equal reports whether two byte slices have the same contents.
 // a: First byte slice
 // b: Second byte slice
 // Returns:
 //   - bool: True if slices are equal
 //
 // Note:
-//   - Uses constant-time comparison to prevent timing attacks
+//   - Not constant-time, it stops at the first difference; use ConstantTimeEqual
+//     for secrets such as MACs, tokens or PoW solutions
 func Equal(a, b []byte) bool {
 	return bytes.Equal(a, b)
 }
 
+// ConstantTimeEqual reports whether two byte slices are equal in constant time.
+// a: Untrusted input, e.g. a MAC received from a client
+// b: Expected value
+// Returns:
+//   - bool: True if slices are equal
+//
+// Note:
+//   - Running time depends only on the lengths, never on where the contents differ
+//   - On a length mismatch b is still compared in full, so the time does not reveal
+//     how much of the input was examined
+func ConstantTimeEqual(a, b []byte) bool {
+	if len(a) != len(b) {
+		subtle.ConstantTimeCompare(b, b)
+		return false
+	}
+	return subtle.ConstantTimeCompare(a, b) == 1
+}
+
+// Zero overwrites b with zeros, e.g. to wipe keys or passwords after use.
+// b: Buffer holding sensitive data
+//
+// Note:
+//   - The stores cannot be elided as dead by the compiler, unlike a plain loop
+//     over a buffer that is not read afterwards
+//   - Only b itself is wiped; copies made earlier (by append growth, string
+//     conversions, a pooled Buffer) are not
+//   - There is no ZeroString: strings are immutable and may share memory with
+//     literals or other strings, so keep secrets in []byte from the start and
+//     avoid converting them to strings (UnsafeString included)
+func Zero(b []byte) {
+	zero(b)
+	runtime.KeepAlive(b)
+}
+
+// zero is kept out of line so the compiler cannot see that the
+// caller never reads b again and drop the stores.
+//
+//go:noinline
+func zero(b []byte) {
+	clear(b)
+}
+
 // XOR performs byte-wise XOR encryption/decryption.
 // data: Input data to process
 // key: Encryption key
@@ -288,6 +733,7 @@ func Equal(a, b []byte) bool {
 //
 // Note:
 //   - Repeating key XOR (Vernam cipher when key length == data length)
+//   - Not encryption, use EncryptGCM to protect data
 func XOR(data, key []byte) []byte {
 	result := make([]byte, len(data))
 	keyLen := len(key)
@@ -301,6 +747,110 @@ func XOR(data, key []byte) []byte {
 	return result
 }
 
+// XORStream applies repeating-key XOR to a stream processed in chunks.
+// It implements cipher.Stream, carrying the key position across calls,
+// so XORing chunk by chunk gives the same output as XOR on the whole data.
+type XORStream struct {
+	key []byte
+	pos int
+}
+
+var _ cipher.Stream = (*XORStream)(nil)
+
+// NewXORStream creates an XORStream starting at the beginning of key.
+// key: XOR key, copied so later changes to it do not affect the stream
+// Returns:
+//   - *XORStream: Stream ready for XORKeyStream
+//
+// Note:
+//   - Not encryption, same caveats as XOR
+func NewXORStream(key []byte) *XORStream {
+	return &XORStream{key: append([]byte(nil), key...)}
+}
+
+// XORKeyStream XORs each byte of src with the key stream and writes the result to dst.
+// dst: Output buffer, at least len(src) bytes; may be the same slice as src
+// src: Input chunk
+//
+// Note:
+//   - Panics if dst is shorter than src, like other cipher.Stream implementations
+//   - With an empty key, src is copied to dst unchanged
+func (x *XORStream) XORKeyStream(dst, src []byte) {
+	if len(dst) < len(src) {
+		panic("bytes: XORStream output smaller than input")
+	}
+	if len(x.key) == 0 {
+		copy(dst, src)
+		return
+	}
+
+	for i, b := range src {
+		dst[i] = b ^ x.key[x.pos]
+		x.pos++
+		if x.pos == len(x.key) {
+			x.pos = 0
+		}
+	}
+}
+
+// EncryptGCM encrypts data with AES-256-GCM.
+// plaintext: Data to encrypt
+// key: 32-byte encryption key
+// Returns:
+//   - []byte: Random nonce followed by the sealed ciphertext and tag
+//   - error: Invalid key length or cipher failure
+//
+// Note:
+//   - A fresh nonce is drawn per call, so encrypting the same data twice gives different output
+func EncryptGCM(plaintext, key []byte) ([]byte, error) {
+	gcm, err := newGCM(key)
+	if err != nil {
+		return nil, err
+	}
+
+	nonce := make([]byte, gcm.NonceSize(), gcm.NonceSize()+len(plaintext)+gcm.Overhead())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, fmt.Errorf("encrypt: nonce generation failed: %w", err)
+	}
+	return gcm.Seal(nonce, nonce, plaintext, nil), nil
+}
+
+// DecryptGCM decrypts data produced by EncryptGCM.
+// ciphertext: Nonce followed by the sealed data and tag
+// key: 32-byte encryption key
+// Returns:
+//   - []byte: Decrypted data
+//   - error: Invalid key length, truncated input or failed authentication
+func DecryptGCM(ciphertext, key []byte) ([]byte, error) {
+	gcm, err := newGCM(key)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(ciphertext) < gcm.NonceSize()+gcm.Overhead() {
+		return nil, errors.New("decrypt: ciphertext too short")
+	}
+	nonce, sealed := ciphertext[:gcm.NonceSize()], ciphertext[gcm.NonceSize():]
+	plaintext, err := gcm.Open(nil, nonce, sealed, nil)
+	if err != nil {
+		return nil, fmt.Errorf("decrypt: authentication failed: %w", err)
+	}
+	return plaintext, nil
+}
+
+// newGCM creates an AES-256-GCM cipher, rejecting keys of any other length
+func newGCM(key []byte) (cipher.AEAD, error) {
+	const keySize = 32
+	if len(key) != keySize {
+		return nil, fmt.Errorf("gcm: key must be %d bytes, got %d", keySize, len(key))
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, fmt.Errorf("gcm: cipher initialization failed: %w", err)
+	}
+	return cipher.NewGCM(block)
+}
+
 // Chunk splits data into fixed-size byte slices.
 // data: Input data to split
 // size: Desired chunk size
@@ -315,12 +865,100 @@ func Chunk(data []byte, size int) [][]byte {
 	}
 
 	chunks := make([][]byte, 0, (len(data)+size-1)/size)
-	for i := 0; i < len(data); i += size {
-		end := i + size
-		if end > len(data) {
-			end = len(data)
-		}
-		chunks = append(chunks, data[i:end])
+	for chunk := range array.Chunks(data, size) {
+		chunks = append(chunks, chunk)
 	}
 	return chunks
 }
+
+// StreamChunk reads r in fixed-size chunks and calls fn for each of them.
+// r: Source of the data
+// size: Chunk size in bytes
+// fn: Callback invoked per chunk; returning an error stops iteration
+// Returns:
+//   - error: The callback error, or a read error other than io.EOF
+//
+// Note:
+//   - Short reads are reassembled, every chunk but the last is exactly size bytes
+//   - The chunk buffer is reused between calls; copy it to retain it
+func StreamChunk(r io.Reader, size int, fn func(chunk []byte) error) error {
+	if size <= 0 {
+		return fmt.Errorf("stream chunk: size must be positive, got %d", size)
+	}
+
+	buf := make([]byte, size)
+	for {
+		n, err := io.ReadFull(r, buf)
+		if n > 0 {
+			if cbErr := fn(buf[:n]); cbErr != nil {
+				return cbErr
+			}
+		}
+		switch {
+		case err == nil:
+			continue
+		case errors.Is(err, io.EOF), errors.Is(err, io.ErrUnexpectedEOF):
+			return nil
+		default:
+			return fmt.Errorf("stream chunk: read failed: %w", err)
+		}
+	}
+}
+
+// TruncateString shortens s to at most maxBytes bytes without splitting a UTF-8 rune.
+// s: Input string
+// maxBytes: Maximum length of the result in bytes, ellipsis included
+// ellipsis: Suffix appended when truncation happens (e.g., "...")
+// Returns:
+//   - string: s unchanged if it fits, otherwise the truncated string with ellipsis
+//
+// Note:
+//   - If ellipsis alone exceeds maxBytes, the ellipsis itself is truncated
+func TruncateString(s string, maxBytes int, ellipsis string) string {
+	if maxBytes <= 0 {
+		return ""
+	}
+	if len(s) <= maxBytes {
+		return s
+	}
+
+	budget := maxBytes - len(ellipsis)
+	if budget < 0 {
+		return ellipsis[:runeBoundary(ellipsis, maxBytes)]
+	}
+	return s[:runeBoundary(s, budget)] + ellipsis
+}
+
+// TruncateBytes is the []byte variant of TruncateString.
+// data: Input UTF-8 data
+// maxBytes: Maximum length of the result in bytes, ellipsis included
+// ellipsis: Suffix appended when truncation happens
+// Returns:
+//   - []byte: data unchanged if it fits, otherwise a new truncated slice with ellipsis
+func TruncateBytes(data []byte, maxBytes int, ellipsis []byte) []byte {
+	if maxBytes <= 0 {
+		return []byte{}
+	}
+	if len(data) <= maxBytes {
+		return data
+	}
+
+	budget := maxBytes - len(ellipsis)
+	if budget < 0 {
+		return append([]byte{}, ellipsis[:runeBoundary(ellipsis, maxBytes)]...)
+	}
+	result := make([]byte, 0, maxBytes)
+	result = append(result, data[:runeBoundary(data, budget)]...)
+	return append(result, ellipsis...)
+}
+
+// runeBoundary returns the largest cut index <= n that does not split a UTF-8 rune.
+func runeBoundary[T string | []byte](data T, n int) int {
+	if n >= len(data) {
+		return len(data)
+	}
+	for n > 0 && !utf8.RuneStart(data[n]) {
+		n--
+	}
+	return n
+}
@@ -0,0 +1,476 @@
+package bytes
+
+import (
+	"errors"
+	"io"
+	"math"
+	"runtime"
+	"strings"
+	"testing"
+	"testing/iotest"
+)
+
+type streamRecord struct {
+	ID      int    `json:"id"`
+	Payload string `json:"payload"`
+}
+
+func TestSerializeToDeserializeFromLargePayload(t *testing.T) {
+	const payloadSize = 10 << 20
+	const recordSize = 1024
+
+	records := make([]streamRecord, payloadSize/recordSize)
+	for i := range records {
+		records[i] = streamRecord{ID: i, Payload: strings.Repeat("x", recordSize)}
+	}
+
+	pr, pw := io.Pipe()
+	go func() {
+		pw.CloseWithError(SerializeTo(pw, records))
+	}()
+
+	got, err := DeserializeFrom[[]streamRecord](pr)
+	if err != nil {
+		t.Fatalf("DeserializeFrom: %v", err)
+	}
+	if len(got) != len(records) {
+		t.Fatalf("got %d records, want %d", len(got), len(records))
+	}
+	for i := range got {
+		if got[i] != records[i] {
+			t.Fatalf("record %d mismatch", i)
+		}
+	}
+}
+
+func TestSerializeToBoundedAllocations(t *testing.T) {
+	const payloadSize = 10 << 20
+	const recordSize = 1024
+
+	records := make([]streamRecord, payloadSize/recordSize)
+	for i := range records {
+		records[i] = streamRecord{ID: i, Payload: strings.Repeat("x", recordSize)}
+	}
+
+	var before, after runtime.MemStats
+	runtime.GC()
+	runtime.ReadMemStats(&before)
+	if err := SerializeTo(io.Discard, records); err != nil {
+		t.Fatalf("SerializeTo: %v", err)
+	}
+	runtime.ReadMemStats(&after)
+
+	// Encoding the whole value at once allocates at least the payload size,
+	// streaming stays well below it (the margin covers sync.Pool drops under -race)
+	if allocated := after.TotalAlloc - before.TotalAlloc; allocated >= payloadSize {
+		t.Fatalf("SerializeTo allocated %d bytes for a %d byte payload", allocated, payloadSize)
+	}
+}
+
+func TestSerializeToMatchesSerialize(t *testing.T) {
+	tests := []any{
+		[]int{1, 2, 3},
+		[]string{},
+		[]int(nil),
+		[]byte("raw"),
+		map[string]int{"a": 1},
+		"<html>",
+	}
+	for _, v := range tests {
+		var sb strings.Builder
+		if err := SerializeTo(&sb, v); err != nil {
+			t.Fatalf("SerializeTo(%v): %v", v, err)
+		}
+		want := string(MustSerialize(v)) + "\n"
+		if sb.String() != want {
+			t.Errorf("SerializeTo(%v) = %q, want %q", v, sb.String(), want)
+		}
+	}
+
+	var sb strings.Builder
+	if err := SerializeTo(&sb, []string{"a", "<b>"}); err != nil {
+		t.Fatalf("SerializeTo: %v", err)
+	}
+	if want := string(MustSerialize([]string{"a", "<b>"})) + "\n"; sb.String() != want {
+		t.Errorf("SerializeTo = %q, want %q", sb.String(), want)
+	}
+}
+
+func TestDeserializeFromErrors(t *testing.T) {
+	if _, err := DeserializeFrom[[]int](strings.NewReader(`{"a":1}`)); err == nil || !strings.HasPrefix(err.Error(), "deserialize:") {
+		t.Errorf("expected deserialize error for object, got %v", err)
+	}
+	if _, err := DeserializeFrom[[]int](strings.NewReader(`[1,"x"]`)); err == nil || !strings.HasPrefix(err.Error(), "deserialize:") {
+		t.Errorf("expected deserialize error for mistyped element, got %v", err)
+	}
+	if v, err := DeserializeFrom[[]int](strings.NewReader(`null`)); err != nil || v != nil {
+		t.Errorf("DeserializeFrom(null) = %v, %v", v, err)
+	}
+}
+
+func TestCompressAlgoRoundTrip(t *testing.T) {
+	data := []byte(strings.Repeat("faraway compression round-trip ", 1000))
+
+	for _, algo := range []Algorithm{GZIP, ZLIB, ZSTD} {
+		for _, level := range []int{0, 1, 9} {
+			compressed, err := CompressAlgo(data, algo, level)
+			if err != nil {
+				t.Fatalf("CompressAlgo(%v, %d): %v", algo, level, err)
+			}
+			if got := DetectAlgorithm(compressed); got != algo {
+				t.Errorf("DetectAlgorithm = %v, want %v", got, algo)
+			}
+
+			decompressed, err := DecompressAlgo(compressed, algo)
+			if err != nil {
+				t.Fatalf("DecompressAlgo(%v): %v", algo, err)
+			}
+			if string(decompressed) != string(data) {
+				t.Fatalf("DecompressAlgo(%v) returned different data", algo)
+			}
+
+			// Auto-detection keeps Decompress working for every algorithm
+			decompressed, err = Decompress(compressed)
+			if err != nil {
+				t.Fatalf("Decompress(%v data): %v", algo, err)
+			}
+			if string(decompressed) != string(data) {
+				t.Fatalf("Decompress(%v data) returned different data", algo)
+			}
+		}
+	}
+}
+
+func TestDecompressAlgoMismatch(t *testing.T) {
+	data := []byte("payload")
+	compressed := map[Algorithm][]byte{
+		GZIP: MustCompress(data, 5),
+	}
+	for _, algo := range []Algorithm{ZLIB, ZSTD} {
+		c, err := CompressAlgo(data, algo, 0)
+		if err != nil {
+			t.Fatalf("CompressAlgo(%v): %v", algo, err)
+		}
+		compressed[algo] = c
+	}
+
+	for from, c := range compressed {
+		for _, to := range []Algorithm{GZIP, ZLIB, ZSTD} {
+			if from == to {
+				continue
+			}
+			if _, err := DecompressAlgo(c, to); err == nil || !strings.HasPrefix(err.Error(), "decompress:") {
+				t.Errorf("DecompressAlgo(%v data, %v) error = %v, want decompress error", from, to, err)
+			}
+		}
+	}
+}
+
+func TestCompressResultNotShared(t *testing.T) {
+	first := MustCompress([]byte("first"), 5)
+	want := append([]byte(nil), first...)
+	MustCompress([]byte("second payload that reuses the pooled buffer"), 5)
+	if string(first) != string(want) {
+		t.Fatal("Compress result was overwritten by a later call")
+	}
+}
+
+func TestConstantTimeEqual(t *testing.T) {
+	tests := []struct {
+		a, b []byte
+		want bool
+	}{
+		{nil, nil, true},
+		{[]byte{}, nil, true},
+		{[]byte("token"), []byte("token"), true},
+		{[]byte("token"), []byte("tokem"), false},
+		{[]byte("token"), []byte("Token"), false},
+		{[]byte("token"), []byte("token!"), false},
+		{[]byte("token!"), []byte("token"), false},
+		{nil, []byte("token"), false},
+	}
+	for _, tt := range tests {
+		if got := ConstantTimeEqual(tt.a, tt.b); got != tt.want {
+			t.Errorf("ConstantTimeEqual(%q, %q) = %v, want %v", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
+
+// BenchmarkConstantTimeEqual compares inputs differing in the first and in the last
+// byte; with a constant-time comparison both take about as long. The timings are
+// only reported, wall-clock ratios are too noisy to assert on in a test.
+func BenchmarkConstantTimeEqual(b *testing.B) {
+	const size = 64 << 10
+	expected := make([]byte, size)
+	diffFirst := make([]byte, size)
+	diffFirst[0] = 1
+	diffLast := make([]byte, size)
+	diffLast[size-1] = 1
+
+	for _, bm := range []struct {
+		name  string
+		input []byte
+	}{{"diff_first", diffFirst}, {"diff_last", diffLast}} {
+		b.Run(bm.name, func(b *testing.B) {
+			b.SetBytes(size)
+			for i := 0; i < b.N; i++ {
+				if ConstantTimeEqual(bm.input, expected) {
+					b.Fatal("inputs compared equal")
+				}
+			}
+		})
+	}
+}
+
+func TestVarintRoundTrip(t *testing.T) {
+	for _, v := range []uint64{0, 1, 127, 128, 300, 1 << 32, math.MaxUint64} {
+		buf := AppendUvarint([]byte{0xff}, v)
+		got, n, err := Uvarint(buf[1:])
+		if err != nil || got != v || n != len(buf)-1 {
+			t.Errorf("Uvarint(AppendUvarint(%d)) = %d, %d, %v", v, got, n, err)
+		}
+	}
+	for _, v := range []int64{0, 1, -1, 63, -64, 64, math.MinInt64, math.MaxInt64} {
+		buf := AppendVarint(nil, v)
+		got, n, err := Varint(buf)
+		if err != nil || got != v || n != len(buf) {
+			t.Errorf("Varint(AppendVarint(%d)) = %d, %d, %v", v, got, n, err)
+		}
+	}
+}
+
+func TestVarintErrors(t *testing.T) {
+	if _, _, err := Uvarint(nil); !errors.Is(err, ErrVarintTruncated) {
+		t.Errorf("Uvarint(nil) error = %v, want ErrVarintTruncated", err)
+	}
+	if _, _, err := Uvarint([]byte{0x80, 0x80}); !errors.Is(err, ErrVarintTruncated) {
+		t.Errorf("Uvarint(truncated) error = %v, want ErrVarintTruncated", err)
+	}
+	overflow := []byte{0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0x01}
+	if _, _, err := Uvarint(overflow); !errors.Is(err, ErrVarintOverflow) {
+		t.Errorf("Uvarint(overflow) error = %v, want ErrVarintOverflow", err)
+	}
+	if _, _, err := Varint(overflow); !errors.Is(err, ErrVarintOverflow) {
+		t.Errorf("Varint(overflow) error = %v, want ErrVarintOverflow", err)
+	}
+}
+
+// shortReader returns at most max bytes per Read call.
+type shortReader struct {
+	r   io.Reader
+	max int
+}
+
+func (s *shortReader) Read(p []byte) (int, error) {
+	if len(p) > s.max {
+		p = p[:s.max]
+	}
+	return s.r.Read(p)
+}
+
+func TestStreamChunkShortReads(t *testing.T) {
+	data := []byte(strings.Repeat("0123456789", 10)) // 100 bytes
+
+	var chunks []string
+	err := StreamChunk(&shortReader{r: strings.NewReader(string(data)), max: 3}, 16, func(chunk []byte) error {
+		chunks = append(chunks, string(chunk))
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("StreamChunk: %v", err)
+	}
+
+	if len(chunks) != 7 {
+		t.Fatalf("got %d chunks, want 7", len(chunks))
+	}
+	for i, c := range chunks[:len(chunks)-1] {
+		if len(c) != 16 {
+			t.Errorf("chunk %d has %d bytes, want 16", i, len(c))
+		}
+	}
+	if last := chunks[len(chunks)-1]; len(last) != 4 {
+		t.Errorf("last chunk has %d bytes, want 4", len(last))
+	}
+	if got := strings.Join(chunks, ""); got != string(data) {
+		t.Errorf("reassembled data = %q, want %q", got, data)
+	}
+}
+
+func TestStreamChunkStopsOnCallbackError(t *testing.T) {
+	errStop := errors.New("stop")
+	calls := 0
+	err := StreamChunk(strings.NewReader(strings.Repeat("x", 100)), 10, func([]byte) error {
+		calls++
+		if calls == 3 {
+			return errStop
+		}
+		return nil
+	})
+	if !errors.Is(err, errStop) || calls != 3 {
+		t.Fatalf("StreamChunk = %v after %d calls, want errStop after 3", err, calls)
+	}
+}
+
+func TestStreamChunkEdgeCases(t *testing.T) {
+	calls := 0
+	if err := StreamChunk(strings.NewReader(""), 8, func([]byte) error { calls++; return nil }); err != nil || calls != 0 {
+		t.Errorf("empty reader: err = %v, calls = %d", err, calls)
+	}
+	if err := StreamChunk(strings.NewReader("abc"), 0, func([]byte) error { return nil }); err == nil {
+		t.Error("expected error for zero size")
+	}
+	readErr := errors.New("boom")
+	err := StreamChunk(io.MultiReader(strings.NewReader("abc"), iotest.ErrReader(readErr)), 8, func([]byte) error { return nil })
+	if !errors.Is(err, readErr) {
+		t.Errorf("read error = %v, want %v", err, readErr)
+	}
+}
+
+func TestUnsafeStringAliasesSlice(t *testing.T) {
+	data := []byte("hello")
+	s := UnsafeString(data)
+	copied := String(data)
+
+	data[0] = 'j'
+	if s != "jello" {
+		t.Errorf("UnsafeString did not observe the mutation: %q", s)
+	}
+	if copied != "hello" {
+		t.Errorf("String observed the mutation: %q", copied)
+	}
+
+	if UnsafeString(nil) != "" || UnsafeBytes("") != nil {
+		t.Error("empty conversions should return empty values")
+	}
+	if got := UnsafeBytes("world"); string(got) != "world" {
+		t.Errorf("UnsafeBytes = %q", got)
+	}
+}
+
+var benchSink int
+
+func BenchmarkUnsafeString(b *testing.B) {
+	data := []byte(strings.Repeat("x", 1024))
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		benchSink += len(UnsafeString(data))
+	}
+	if allocs := testing.AllocsPerRun(100, func() { benchSink += len(UnsafeString(data)) }); allocs != 0 {
+		b.Fatalf("UnsafeString allocated %v times per call", allocs)
+	}
+}
+
+func BenchmarkUnsafeBytes(b *testing.B) {
+	s := strings.Repeat("x", 1024)
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		benchSink += len(UnsafeBytes(s))
+	}
+	if allocs := testing.AllocsPerRun(100, func() { benchSink += len(UnsafeBytes(s)) }); allocs != 0 {
+		b.Fatalf("UnsafeBytes allocated %v times per call", allocs)
+	}
+}
+
+type dumpAddress struct {
+	City string
+	Zip  int
+}
+
+type dumpPerson struct {
+	Name     string
+	Address  *dumpAddress
+	Friends  []dumpAddress
+	Next     *dumpPerson
+	internal int
+}
+
+func TestDumpStruct(t *testing.T) {
+	p := &dumpPerson{
+		Name:     "Ann",
+		Address:  &dumpAddress{City: "Oslo", Zip: 150},
+		Friends:  []dumpAddress{{City: "Rome", Zip: 1}},
+		internal: 7,
+	}
+	p.Next = p // Cycle
+
+	want := `&dumpPerson{
+  Name: "Ann"
+  Address: &dumpAddress{
+    City: "Oslo"
+    Zip: 150
+  }
+  Friends: [
+    dumpAddress{
+      City: "Rome"
+      Zip: 1
+    }
+  ]
+  Next: <cycle>
+}`
+	if got := Dump(p, "  "); got != want {
+		t.Errorf("Dump() =\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestDumpMapAndSlice(t *testing.T) {
+	data := map[string]interface{}{
+		"b": []interface{}{1, "x"},
+		"a": nil,
+	}
+	want := `{
+  a: <nil>
+  b: [
+    1
+    "x"
+  ]
+}`
+	if got := Dump(data, "  "); got != want {
+		t.Errorf("Dump() =\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestXORStreamChunksMatchOneShot(t *testing.T) {
+	key := []byte("secret-key")
+	data := []byte(strings.Repeat("stream me across chunk boundaries ", 7))
+	want := XOR(data, key)
+
+	got := make([]byte, len(data))
+	stream := NewXORStream(key)
+	// Uneven chunks that don't line up with the key length
+	for _, bounds := range [][2]int{{0, 3}, {3, 50}, {50, len(data)}} {
+		stream.XORKeyStream(got[bounds[0]:bounds[1]], data[bounds[0]:bounds[1]])
+	}
+	if string(got) != string(want) {
+		t.Fatalf("chunked XOR differs from one-shot XOR")
+	}
+
+	// Running the same stream again decrypts in place
+	NewXORStream(key).XORKeyStream(got, got)
+	if string(got) != string(data) {
+		t.Fatalf("XORStream round-trip failed")
+	}
+}
+
+func TestGetPutBuffer(t *testing.T) {
+	buf := GetBuffer()
+	if buf.Len() != 0 {
+		t.Fatalf("GetBuffer returned %d bytes, want empty", buf.Len())
+	}
+	buf.WriteString("payload")
+	PutBuffer(buf)
+	if buf.Len() != 0 {
+		t.Fatalf("PutBuffer left %d bytes in the buffer", buf.Len())
+	}
+	PutBuffer(nil)
+}
+
+func TestZero(t *testing.T) {
+	key := []byte("super secret key material")
+	Zero(key)
+	for i, c := range key {
+		if c != 0 {
+			t.Fatalf("byte %d not wiped: %#x", i, c)
+		}
+	}
+	Zero(nil)
+}
@@ -0,0 +1,212 @@
+package bytes
+
+// EditOp is the kind of change an Edit describes.
+type EditOp int
+
+// Edit operations
+const (
+	EditInsert EditOp = iota // Insert Data before Pos
+	EditDelete               // Delete Len bytes starting at Pos
+)
+
+// Edit is a single step of an edit script produced by Diff.
+// Pos is always an offset into the original data.
+type Edit struct {
+	Op   EditOp
+	Pos  int
+	Len  int    // Number of deleted bytes (EditDelete)
+	Data []byte // Inserted bytes (EditInsert)
+}
+
+// Diff computes a minimal edit script turning a into b.
+// a: Original data
+// b: Target data
+// Returns:
+//   - []Edit: Insertions/deletions ordered by position, empty if a equals b
+//
+// Notes:
+//   - Uses Myers' O((N+M)·D) algorithm, where D is the size of the edit script,
+//     in its linear-space variant: memory is O(N+M) however far apart a and b are
+//   - Inserted Data aliases b
+func Diff(a, b []byte) []Edit {
+	return diffRange(a, b, 0, make([]Edit, 0))
+}
+
+// diffRange appends the edit script turning a into b to edits, a starting at offset pos
+// of the original data. It splits the problem at the middle snake and recurses on both halves.
+func diffRange(a, b []byte, pos int, edits []Edit) []Edit {
+	// Common prefix and suffix never need edits, skip them up front
+	prefix := 0
+	for prefix < len(a) && prefix < len(b) && a[prefix] == b[prefix] {
+		prefix++
+	}
+	a, b, pos = a[prefix:], b[prefix:], pos+prefix
+	suffix := 0
+	for suffix < len(a) && suffix < len(b) && a[len(a)-1-suffix] == b[len(b)-1-suffix] {
+		suffix++
+	}
+	a, b = a[:len(a)-suffix], b[:len(b)-suffix]
+
+	switch {
+	case len(a) == 0 && len(b) == 0:
+		return edits
+	case len(a) == 0:
+		return appendEdit(edits, Edit{Op: EditInsert, Pos: pos, Data: b})
+	case len(b) == 0:
+		return appendEdit(edits, Edit{Op: EditDelete, Pos: pos, Len: len(a)})
+	}
+
+	x, y, ok := middleSnake(a, b)
+	if !ok {
+		// Nothing in common: replace a with b
+		edits = appendEdit(edits, Edit{Op: EditDelete, Pos: pos, Len: len(a)})
+		return appendEdit(edits, Edit{Op: EditInsert, Pos: pos + len(a), Data: b})
+	}
+	edits = diffRange(a[:x], b[:y], pos, edits)
+	return diffRange(a[x:], b[y:], pos+x, edits)
+}
+
+// middleSnake runs Myers' search from both ends of a and b at once and returns the point
+// (x, y) where the paths meet, which lies on an optimal edit path. ok is false if a and b
+// have no byte in common. Only two diagonal arrays of size N+M are kept.
+func middleSnake(a, b []byte) (x, y int, ok bool) {
+	n, m := len(a), len(b)
+	maxD := (n + m + 1) / 2
+	offset := maxD
+	forward := make([]int, 2*maxD+2)
+	backward := make([]int, 2*maxD+2)
+	for i := range forward {
+		forward[i] = -1
+		backward[i] = -1
+	}
+	forward[offset+1] = 0
+	backward[offset+1] = 0
+
+	delta := n - m
+	// With an odd delta the paths meet during a forward step, otherwise during a backward one
+	checkForward := delta%2 != 0
+	// Diagonals that ran off the grid are skipped on later steps
+	fStart, fEnd, bStart, bEnd := 0, 0, 0, 0
+
+	for d := 0; d < maxD; d++ {
+		for k := -d + fStart; k <= d-fEnd; k += 2 {
+			i := offset + k
+			var x1 int
+			if k == -d || (k != d && forward[i-1] < forward[i+1]) {
+				x1 = forward[i+1] // Move down: insertion
+			} else {
+				x1 = forward[i-1] + 1 // Move right: deletion
+			}
+			y1 := x1 - k
+			for x1 < n && y1 < m && a[x1] == b[y1] {
+				x1++
+				y1++
+			}
+			forward[i] = x1
+			switch {
+			case x1 > n:
+				fEnd += 2
+			case y1 > m:
+				fStart += 2
+			case checkForward:
+				if j := offset + delta - k; j >= 0 && j < len(backward) && backward[j] != -1 {
+					if x1 >= n-backward[j] {
+						return x1, y1, true
+					}
+				}
+			}
+		}
+
+		for k := -d + bStart; k <= d-bEnd; k += 2 {
+			i := offset + k
+			var x2 int
+			if k == -d || (k != d && backward[i-1] < backward[i+1]) {
+				x2 = backward[i+1]
+			} else {
+				x2 = backward[i-1] + 1
+			}
+			y2 := x2 - k
+			for x2 < n && y2 < m && a[n-1-x2] == b[m-1-y2] {
+				x2++
+				y2++
+			}
+			backward[i] = x2
+			switch {
+			case x2 > n:
+				bEnd += 2
+			case y2 > m:
+				bStart += 2
+			case !checkForward:
+				if j := offset + delta - k; j >= 0 && j < len(forward) && forward[j] != -1 {
+					x1 := forward[j]
+					if x1 >= n-x2 {
+						return x1, x1 - (j - offset), true
+					}
+				}
+			}
+		}
+	}
+	return 0, 0, false
+}
+
+// appendEdit appends e to edits, merging it into the last edit when both are
+// insertions of consecutive bytes of b at the same position or adjacent deletions.
+func appendEdit(edits []Edit, e Edit) []Edit {
+	if len(edits) > 0 {
+		last := &edits[len(edits)-1]
+		// Data slices share b's backing array, so equal remaining capacities past
+		// last.Data and before e.Data mean e.Data directly follows it in b
+		if e.Op == EditInsert && last.Op == EditInsert && last.Pos == e.Pos &&
+			cap(last.Data)-len(last.Data) == cap(e.Data) {
+			last.Data = last.Data[:len(last.Data)+len(e.Data)]
+			return edits
+		}
+		if e.Op == EditDelete && last.Op == EditDelete && last.Pos+last.Len == e.Pos {
+			last.Len += e.Len
+			return edits
+		}
+	}
+	return append(edits, e)
+}
+
+// Patch applies an edit script produced by Diff to a.
+// a: Original data
+// edits: Edit script ordered by position
+// Returns:
+//   - []byte: Reconstructed data, or nil if the edits don't fit a
+func Patch(a []byte, edits []Edit) []byte {
+	size := len(a)
+	for _, e := range edits {
+		if e.Op == EditInsert {
+			size += len(e.Data)
+		} else {
+			size -= e.Len
+		}
+	}
+	if size < 0 {
+		return nil
+	}
+
+	result := make([]byte, 0, size)
+	cursor := 0
+	for _, e := range edits {
+		if e.Pos < cursor || e.Pos > len(a) {
+			return nil
+		}
+		result = append(result, a[cursor:e.Pos]...)
+		cursor = e.Pos
+
+		switch e.Op {
+		case EditInsert:
+			result = append(result, e.Data...)
+		case EditDelete:
+			if e.Len < 0 || cursor+e.Len > len(a) {
+				return nil
+			}
+			cursor += e.Len
+		default:
+			return nil
+		}
+	}
+	return append(result, a[cursor:]...)
+}
@@ -0,0 +1,94 @@
+package bytes
+
+import (
+	"bytes"
+	"math/rand/v2"
+	"runtime"
+	"testing"
+)
+
+// editSize returns the number of inserted and deleted bytes of an edit script.
+func editSize(edits []Edit) int {
+	size := 0
+	for _, e := range edits {
+		size += e.Len + len(e.Data)
+	}
+	return size
+}
+
+func randomBytes(r *rand.Rand, n int) []byte {
+	b := make([]byte, n)
+	for i := range b {
+		b[i] = byte(r.IntN(256))
+	}
+	return b
+}
+
+func TestDiffPatchRoundTrip(t *testing.T) {
+	r := rand.New(rand.NewPCG(1, 2))
+	edited := randomBytes(r, 1000)
+	edited = append(edited[:100:100], append([]byte("inserted"), edited[300:]...)...)
+
+	tests := []struct {
+		name string
+		a, b []byte
+		size int // Expected edit size, -1 to skip the check
+	}{
+		{name: "both empty", a: nil, b: nil, size: 0},
+		{name: "empty original", a: nil, b: []byte("hello"), size: 5},
+		{name: "empty target", a: []byte("hello"), b: []byte{}, size: 5},
+		{name: "identical", a: []byte("same bytes"), b: []byte("same bytes"), size: 0},
+		{name: "disjoint", a: []byte("aaaa"), b: []byte("bbbbbb"), size: 10},
+		{name: "classic", a: []byte("abcabba"), b: []byte("cbabac"), size: 5},
+		{name: "interleaved", a: []byte("axbxcx"), b: []byte("abc"), size: 3},
+		{name: "edited block", a: randomBytes(rand.New(rand.NewPCG(1, 2)), 1000), b: edited, size: -1},
+		{name: "random 3KB", a: randomBytes(r, 3000), b: randomBytes(r, 3000), size: -1},
+	}
+	for _, tt := range tests {
+		edits := Diff(tt.a, tt.b)
+		if edits == nil {
+			t.Errorf("%s: got nil edit script, want empty", tt.name)
+		}
+		if got := Patch(tt.a, edits); !bytes.Equal(got, tt.b) {
+			t.Errorf("%s: Patch(a, Diff(a, b)) does not reproduce b", tt.name)
+		}
+		if tt.size >= 0 && editSize(edits) != tt.size {
+			t.Errorf("%s: edit size %d, want %d", tt.name, editSize(edits), tt.size)
+		}
+	}
+}
+
+func TestDiffCoalescesEdits(t *testing.T) {
+	edits := Diff([]byte("abcdef"), []byte("abXYZf"))
+	want := []Edit{
+		{Op: EditDelete, Pos: 2, Len: 3},
+		{Op: EditInsert, Pos: 5, Data: []byte("XYZ")},
+	}
+	if len(edits) != len(want) {
+		t.Fatalf("got %+v, want %+v", edits, want)
+	}
+	for i := range want {
+		if edits[i].Op != want[i].Op || edits[i].Pos != want[i].Pos ||
+			edits[i].Len != want[i].Len || !bytes.Equal(edits[i].Data, want[i].Data) {
+			t.Fatalf("got %+v, want %+v", edits, want)
+		}
+	}
+}
+
+func TestDiffMemoryIsLinear(t *testing.T) {
+	r := rand.New(rand.NewPCG(3, 4))
+	a, b := randomBytes(r, 3000), randomBytes(r, 3000)
+
+	var before, after runtime.MemStats
+	runtime.ReadMemStats(&before)
+	edits := Diff(a, b)
+	runtime.ReadMemStats(&after)
+
+	// A full V array copy per edit step allocated hundreds of MB here
+	if alloc := after.TotalAlloc - before.TotalAlloc; alloc > 16<<20 {
+		t.Fatalf("Diff of two 3 KB inputs allocated %d bytes", alloc)
+	}
+	if !bytes.Equal(Patch(a, edits), b) {
+		t.Fatal("Patch(a, Diff(a, b)) does not reproduce b")
+	}
+}
@@ -0,0 +1,70 @@
+package bytes
+
+import (
+	"encoding/binary"
+	"errors"
+)
+
+// Varint decoding errors
+var (
+	ErrVarintTruncated = errors.New("varint: buffer ends before the value is complete")
+	ErrVarintOverflow  = errors.New("varint: value overflows 64 bits")
+)
+
+// AppendUvarint appends the varint encoding of v to dst.
+// dst: Buffer to append to (may be nil)
+// v: Value to encode
+// Returns:
+//   - []byte: dst extended by 1 to 10 bytes
+func AppendUvarint(dst []byte, v uint64) []byte {
+	return binary.AppendUvarint(dst, v)
+}
+
+// Uvarint decodes a varint from the start of src.
+// src: Encoded data
+// Returns:
+//   - uint64: Decoded value
+//   - int: Number of bytes consumed
+//   - error: ErrVarintTruncated or ErrVarintOverflow if src does not start with a valid value
+func Uvarint(src []byte) (uint64, int, error) {
+	v, n := binary.Uvarint(src)
+	if err := varintError(n); err != nil {
+		return 0, 0, err
+	}
+	return v, n, nil
+}
+
+// AppendVarint appends the zig-zag varint encoding of v to dst.
+// dst: Buffer to append to (may be nil)
+// v: Value to encode, small magnitudes of either sign encode compactly
+// Returns:
+//   - []byte: dst extended by 1 to 10 bytes
+func AppendVarint(dst []byte, v int64) []byte {
+	return binary.AppendVarint(dst, v)
+}
+
+// Varint decodes a zig-zag varint from the start of src.
+// src: Encoded data
+// Returns:
+//   - int64: Decoded value
+//   - int: Number of bytes consumed
+//   - error: ErrVarintTruncated or ErrVarintOverflow if src does not start with a valid value
+func Varint(src []byte) (int64, int, error) {
+	v, n := binary.Varint(src)
+	if err := varintError(n); err != nil {
+		return 0, 0, err
+	}
+	return v, n, nil
+}
+
+// varintError maps the byte count returned by encoding/binary to an error
+func varintError(n int) error {
+	switch {
+	case n == 0:
+		return ErrVarintTruncated
+	case n < 0:
+		return ErrVarintOverflow
+	default:
+		return nil
+	}
+}
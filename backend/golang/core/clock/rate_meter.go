@@ -0,0 +1,125 @@
+package clock
+
+import (
+	"math"
+	"sync"
+	"time"
+)
+
+// tickInterval is how often the moving averages are updated.
+const tickInterval = 5 * time.Second
+
+// ewma is an exponentially weighted moving average of a per-second rate.
+type ewma struct {
+	alpha       float64
+	rate        float64
+	initialized bool
+}
+
+func newEWMA(window time.Duration) *ewma {
+	return &ewma{alpha: 1 - math.Exp(-tickInterval.Seconds()/window.Seconds())}
+}
+
+// tick folds the events counted during one interval into the average.
+func (e *ewma) tick(count int64) {
+	instant := float64(count) / tickInterval.Seconds()
+	if e.initialized {
+		e.rate += e.alpha * (instant - e.rate)
+		return
+	}
+	e.rate = instant
+	e.initialized = true
+}
+
+// RateMeter records events and reports their rate as 1- and 5-minute
+// exponentially weighted moving averages plus the mean rate, like a Dropwizard meter.
+// All time is read from the injected Clock, so a fake clock makes it deterministic.
+// Safe for concurrent use.
+type RateMeter struct {
+	clock Clock
+
+	mu        sync.Mutex
+	count     int64
+	uncounted int64 // Events since the last tick
+	start     time.Time
+	lastTick  time.Time
+	m1        *ewma
+	m5        *ewma
+}
+
+// NewRateMeter creates a RateMeter using the given clock (system time if nil).
+func NewRateMeter(c Clock) *RateMeter {
+	if c == nil {
+		c = New()
+	}
+	now := c.Now()
+	return &RateMeter{
+		clock:    c,
+		start:    now,
+		lastTick: now,
+		m1:       newEWMA(time.Minute),
+		m5:       newEWMA(5 * time.Minute),
+	}
+}
+
+// Mark records n events.
+func (m *RateMeter) Mark(n int64) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.tickIfNeeded()
+	m.count += n
+	m.uncounted += n
+}
+
+// Count returns the total number of recorded events.
+func (m *RateMeter) Count() int64 {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.count
+}
+
+// Rate1 returns the one-minute moving average rate in events per second.
+func (m *RateMeter) Rate1() float64 {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.tickIfNeeded()
+	return m.m1.rate
+}
+
+// Rate5 returns the five-minute moving average rate in events per second.
+func (m *RateMeter) Rate5() float64 {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.tickIfNeeded()
+	return m.m5.rate
+}
+
+// RateMean returns the mean rate in events per second since the meter was created.
+func (m *RateMeter) RateMean() float64 {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	elapsed := m.clock.Since(m.start).Seconds()
+	if elapsed <= 0 {
+		return 0
+	}
+	return float64(m.count) / elapsed
+}
+
+// tickIfNeeded catches up on all tick intervals that elapsed since the last one,
+// assumes lock is already held.
+func (m *RateMeter) tickIfNeeded() {
+	ticks := int64(m.clock.Since(m.lastTick) / tickInterval)
+	if ticks <= 0 {
+		return
+	}
+	m.lastTick = m.lastTick.Add(time.Duration(ticks) * tickInterval)
+
+	// Pending events belong to the first interval, the rest were idle
+	m.m1.tick(m.uncounted)
+	m.m5.tick(m.uncounted)
+	m.uncounted = 0
+	for i := int64(1); i < ticks; i++ {
+		m.m1.tick(0)
+		m.m5.tick(0)
+	}
+}
@@ -0,0 +1,119 @@
+package closer
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+)
+
+// DAGCloser closes named resources in dependency order: a resource is closed only
+// after everything that depends on it, regardless of registration order.
+// Independent resources are closed in reverse registration order, like LIFOCloser.
+type DAGCloser struct {
+	mu      sync.Mutex
+	names   []string          // Registration order
+	closers map[string]Closer // Resource name -> closer
+	deps    map[string][]string
+}
+
+// NewDAGCloser creates a new DAGCloser instance.
+func NewDAGCloser() *DAGCloser {
+	return &DAGCloser{
+		names:   make([]string, 0),
+		closers: make(map[string]Closer),
+		deps:    make(map[string][]string),
+	}
+}
+
+// Add registers a resource without dependencies.
+func (dc *DAGCloser) Add(name string, c Closer) error {
+	return dc.AddAfter(name, c)
+}
+
+// AddAfter registers a resource that depends on (uses) the dependsOn resources,
+// so it is closed before them. Dependencies may be registered later;
+// ones never registered are ignored by Close.
+// Returns an error for a duplicate name or a dependency cycle.
+func (dc *DAGCloser) AddAfter(name string, c Closer, dependsOn ...string) error {
+	dc.mu.Lock()
+	defer dc.mu.Unlock()
+
+	if _, ok := dc.closers[name]; ok {
+		return fmt.Errorf("closer: resource %q already registered", name)
+	}
+	for _, dep := range dependsOn {
+		if dep == name || dc.reaches(dep, name) {
+			return fmt.Errorf("closer: dependency of %q on %q creates a cycle", name, dep)
+		}
+	}
+
+	dc.names = append(dc.names, name)
+	dc.closers[name] = c
+	dc.deps[name] = append([]string(nil), dependsOn...)
+	return nil
+}
+
+// reaches reports whether to is a transitive dependency of from,
+// assumes lock is already held.
+func (dc *DAGCloser) reaches(from, to string) bool {
+	seen := make(map[string]bool)
+	stack := []string{from}
+	for len(stack) > 0 {
+		n := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		if n == to {
+			return true
+		}
+		if seen[n] {
+			continue
+		}
+		seen[n] = true
+		stack = append(stack, dc.deps[n]...)
+	}
+	return false
+}
+
+// Close closes all registered resources, each one after all of its dependents.
+// Returns joined errors if any closers failed.
+// Ensures all resources are closed regardless of individual errors.
+func (dc *DAGCloser) Close() error {
+	dc.mu.Lock()
+	defer dc.mu.Unlock()
+
+	// Count the registered dependents of every resource
+	dependents := make(map[string]int, len(dc.names))
+	for _, name := range dc.names {
+		for _, dep := range dc.deps[name] {
+			if _, ok := dc.closers[dep]; ok {
+				dependents[dep]++
+			}
+		}
+	}
+
+	var errs []error
+	closed := make(map[string]bool, len(dc.names))
+	for len(closed) < len(dc.names) {
+		// Pick the latest registered resource nothing open depends on;
+		// one always exists because cycles are rejected by AddAfter.
+		var next string
+		for i := len(dc.names) - 1; i >= 0; i-- {
+			if name := dc.names[i]; !closed[name] && dependents[name] == 0 {
+				next = name
+				break
+			}
+		}
+
+		if err := dc.closers[next].Close(); err != nil {
+			errs = append(errs, fmt.Errorf("close %s: %w", next, err))
+		}
+		closed[next] = true
+		for _, dep := range dc.deps[next] {
+			dependents[dep]--
+		}
+	}
+
+	if len(errs) > 0 {
+		return errors.Join(errs...)
+	}
+	return nil
+}
@@ -3,9 +3,17 @@ module github.com/RRWM1rr0rB/faraway_lib/backend/golang/core
 go 1.24.1
 
 require (
+	github.com/RRWM1rr0rB/faraway_lib/backend/golang/errors v0.0.0-20250331145437-1c4c07eac7c2
 	github.com/google/uuid v1.6.0
 	github.com/gorilla/websocket v1.5.3
+	github.com/klauspost/compress v1.18.0
 	github.com/oklog/ulid/v2 v2.1.0
 	golang.org/x/exp v0.0.0-20250305212735-054e65f0b394
 	golang.org/x/sync v0.12.0
+	golang.org/x/sys v0.30.0
+)
+
+require (
+	github.com/hashicorp/errwrap v1.0.0 // indirect
+	github.com/hashicorp/go-multierror v1.1.1 // indirect
 )
@@ -0,0 +1,103 @@
+package random
+
+import (
+	"errors"
+	"math"
+	"math/rand/v2"
+)
+
+// AliasSampler draws indexes from a fixed discrete distribution in O(1)
+// using Walker's alias method (Vose's variant).
+// It is immutable after construction and safe for concurrent use
+// as long as each goroutine passes its own random source.
+type AliasSampler struct {
+	prob  []float64
+	alias []int
+}
+
+// NewAliasSampler builds a sampler for the given relative weights.
+// Args:
+//   - weights: Non-negative weights, at least one must be positive
+//
+// Returns:
+//   - *AliasSampler: Sampler returning index i with probability weights[i]/sum(weights)
+//   - error: If weights are empty, negative, non-finite or all zero
+func NewAliasSampler(weights []float64) (*AliasSampler, error) {
+	n := len(weights)
+	if n == 0 {
+		return nil, errors.New("random: weights must not be empty")
+	}
+
+	var total float64
+	for _, w := range weights {
+		if w < 0 || math.IsNaN(w) || math.IsInf(w, 0) {
+			return nil, errors.New("random: weights must be finite and non-negative")
+		}
+		total += w
+	}
+	if total == 0 {
+		return nil, errors.New("random: at least one weight must be positive")
+	}
+
+	s := &AliasSampler{
+		prob:  make([]float64, n),
+		alias: make([]int, n),
+	}
+
+	// Scale weights so the average is 1, then split into under- and over-full buckets
+	scaled := make([]float64, n)
+	small := make([]int, 0, n)
+	large := make([]int, 0, n)
+	for i, w := range weights {
+		scaled[i] = w * float64(n) / total
+		if scaled[i] < 1 {
+			small = append(small, i)
+		} else {
+			large = append(large, i)
+		}
+	}
+
+	for len(small) > 0 && len(large) > 0 {
+		l := small[len(small)-1]
+		small = small[:len(small)-1]
+		g := large[len(large)-1]
+		large = large[:len(large)-1]
+
+		s.prob[l] = scaled[l]
+		s.alias[l] = g
+
+		scaled[g] = scaled[g] + scaled[l] - 1
+		if scaled[g] < 1 {
+			small = append(small, g)
+		} else {
+			large = append(large, g)
+		}
+	}
+
+	// Leftovers are full buckets up to floating point error
+	for _, i := range large {
+		s.prob[i] = 1
+	}
+	for _, i := range small {
+		s.prob[i] = 1
+	}
+
+	return s, nil
+}
+
+// Sample draws a random index.
+// Args:
+//   - r: Optional random source (uses default if nil)
+//
+// Returns:
+//   - int: Index into the weights the sampler was built from
+func (s *AliasSampler) Sample(r *rand.Rand) int {
+	if r == nil {
+		r = defaultRand
+	}
+	i := r.IntN(len(s.prob))
+	if r.Float64() < s.prob[i] {
+		return i
+	}
+	return s.alias[i]
+}
@@ -0,0 +1,43 @@
+package random
+
+import (
+	"math/rand/v2"
+	"sync"
+)
+
+// Pool hands out independently seeded generators so goroutines never share one.
+// A *rand.Rand is not safe for concurrent use; under heavy concurrency take a
+// generator from a Pool and pass it to the functions of this package instead
+// of relying on the shared default source used for a nil r.
+type Pool struct {
+	pool sync.Pool
+}
+
+// NewPool creates a Pool of PCG generators, each seeded from the runtime's
+// concurrency-safe global source.
+func NewPool() *Pool {
+	return &Pool{
+		pool: sync.Pool{
+			New: func() any {
+				return rand.New(rand.NewPCG(rand.Uint64(), rand.Uint64()))
+			},
+		},
+	}
+}
+
+// Get returns a generator for exclusive use by the caller until it is Put back.
+// Returns:
+//   - *rand.Rand: Generator, not to be shared between goroutines
+func (p *Pool) Get() *rand.Rand {
+	return p.pool.Get().(*rand.Rand)
+}
+
+// Put returns a generator obtained from Get to the pool.
+// Args:
+//   - r: Generator to return; nil is ignored
+func (p *Pool) Put(r *rand.Rand) {
+	if r == nil {
+		return
+	}
+	p.pool.Put(r)
+}
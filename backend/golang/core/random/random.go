@@ -9,8 +9,9 @@ import (
 	"time"
 )
 
-// defaultRand is the shared random source seeded with current time.
-// Thread-safe according to math/rand/v2 specifications.
+// defaultRand is the shared random source seeded with current time, used when r is nil.
+// A *rand.Rand is not safe for concurrent use, so concurrent callers should pass their
+// own source, e.g. from a Pool, rather than relying on the nil convenience path.
 var defaultRand = rand.New(rand.NewPCG(
 	uint64(time.Now().UnixNano()),
 	uint64(time.Now().UnixNano()),
@@ -43,10 +43,6 @@ func (c *ClientWithRetry) Do(req *http.Request) (*http.Response, error) {
 	return resp, err
 }
 
-type TemporaryError struct {
-	StatusCode int
-}
-
 // TemporaryError represents a temporary error response.
 type TemporaryError struct {
 	StatusCode int
@@ -0,0 +1,82 @@
+//go:build debuglock
+
+package safe
+
+import (
+	"log/slog"
+	"runtime/debug"
+	"sync"
+	"time"
+)
+
+// defaultLockWarnThreshold is used when DebugMutex.Threshold is zero.
+const defaultLockWarnThreshold = time.Second
+
+// DebugMutex is a sync.Mutex that, in builds with the debuglock tag, remembers the
+// stack of the current holder and logs a warning when Lock waits longer than Threshold.
+// Without the tag it is a plain mutex with no extra overhead.
+type DebugMutex struct {
+	Threshold time.Duration // Lock wait before a warning is logged, 0 means one second
+
+	mu sync.Mutex
+
+	meta       sync.Mutex // Guards holder and acquiredAt
+	holder     []byte     // Stack of the goroutine holding mu
+	acquiredAt time.Time
+}
+
+// Lock locks m, warning if the wait exceeds the threshold.
+func (m *DebugMutex) Lock() {
+	if m.mu.TryLock() {
+		m.acquired()
+		return
+	}
+
+	threshold := m.Threshold
+	if threshold <= 0 {
+		threshold = defaultLockWarnThreshold
+	}
+	waiter := debug.Stack()
+	start := time.Now()
+	timer := time.AfterFunc(threshold, func() {
+		m.meta.Lock()
+		holder, heldFor := m.holder, time.Since(m.acquiredAt)
+		m.meta.Unlock()
+		slog.Warn("safe: lock wait exceeded threshold, possible deadlock",
+			"waited", time.Since(start),
+			"held_for", heldFor,
+			"holder_stack", string(holder),
+			"waiter_stack", string(waiter),
+		)
+	})
+
+	m.mu.Lock()
+	timer.Stop()
+	m.acquired()
+}
+
+// TryLock tries to lock m and reports whether it succeeded.
+func (m *DebugMutex) TryLock() bool {
+	if !m.mu.TryLock() {
+		return false
+	}
+	m.acquired()
+	return true
+}
+
+// Unlock unlocks m.
+func (m *DebugMutex) Unlock() {
+	m.meta.Lock()
+	m.holder = nil
+	m.meta.Unlock()
+	m.mu.Unlock()
+}
+
+// acquired records the caller as the holder of m.
+func (m *DebugMutex) acquired() {
+	stack := debug.Stack()
+	m.meta.Lock()
+	m.holder = stack
+	m.acquiredAt = time.Now()
+	m.meta.Unlock()
+}
@@ -0,0 +1,26 @@
+//go:build !debuglock
+
+package safe
+
+import (
+	"sync"
+	"time"
+)
+
+// DebugMutex is a sync.Mutex that, in builds with the debuglock tag, remembers the
+// stack of the current holder and logs a warning when Lock waits longer than Threshold.
+// Without the tag it is a plain mutex with no extra overhead.
+type DebugMutex struct {
+	Threshold time.Duration // Lock wait before a warning is logged, 0 means one second
+
+	mu sync.Mutex
+}
+
+// Lock locks m.
+func (m *DebugMutex) Lock() { m.mu.Lock() }
+
+// TryLock tries to lock m and reports whether it succeeded.
+func (m *DebugMutex) TryLock() bool { return m.mu.TryLock() }
+
+// Unlock unlocks m.
+func (m *DebugMutex) Unlock() { m.mu.Unlock() }
@@ -10,11 +10,14 @@ import (
 	"sync"
 
 	"golang.org/x/sync/errgroup"
+
+	"github.com/RRWM1rr0rB/faraway_lib/backend/golang/errors"
 )
 
 // SafeGroup enhances errgroup.Group with panic recovery and error aggregation.
 type SafeGroup struct {
 	eg      *errgroup.Group
+	parent  context.Context // Context the group was derived from
 	ctx     context.Context
 	mu      sync.Mutex
 	errs    []error
@@ -41,11 +44,13 @@ func WithRecover(recover RecoverFunc) Option {
 
 // WithContext initializes a SafeGroup with a context and options.
 func WithContext(ctx context.Context, opts ...Option) (*SafeGroup, context.Context) {
+	parent := ctx
 	eg, ctx := errgroup.WithContext(ctx)
 	g := &SafeGroup{
-		eg:   eg,
-		ctx:  ctx,
-		errs: make([]error, 0),
+		eg:     eg,
+		parent: parent,
+		ctx:    ctx,
+		errs:   make([]error, 0),
 	}
 	for _, opt := range opts {
 		opt(g)
@@ -63,14 +68,14 @@ func (g *SafeGroup) Go(fn func(ctx context.Context) error) {
 		defer func() {
 			if r := recover(); r != nil {
 				g.recover(r)
-				err = fmt.Errorf("panic: %v\n%s", r, string(debug.Stack()))
+				err = errors.FromPanic(r)
 				g.mu.Lock()
 				g.errs = append(g.errs, err)
 				g.mu.Unlock()
 			}
 		}()
 		err = fn(g.ctx)
-		if err != nil {
+		if err != nil && !g.cancelledBySibling(err) {
 			g.mu.Lock()
 			g.errs = append(g.errs, err)
 			g.mu.Unlock()
@@ -79,14 +84,18 @@ func (g *SafeGroup) Go(fn func(ctx context.Context) error) {
 	})
 }
 
+// cancelledBySibling reports whether err is just a task reacting to the group context
+// being cancelled after another task failed, rather than a failure of its own.
+func (g *SafeGroup) cancelledBySibling(err error) bool {
+	return errors.Is(err, context.Canceled) && g.ctx.Err() != nil && g.parent.Err() == nil
+}
+
 // Wait blocks until all goroutines complete and returns aggregated errors.
+// Cancellation errors of tasks stopped because a sibling failed are left out,
+// so only the root cause(s) are reported.
 func (g *SafeGroup) Wait() error {
-	err := g.eg.Wait()
-	if err != nil {
-		g.mu.Lock()
-		g.errs = append(g.errs, err)
-		g.mu.Unlock()
-	}
+	// The first error is already collected by Go
+	_ = g.eg.Wait()
 	g.mu.Lock()
 	defer g.mu.Unlock()
 	if len(g.errs) > 0 {
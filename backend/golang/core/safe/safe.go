@@ -3,9 +3,10 @@ package safe
 
 import (
 	"context"
-	"fmt"
 	"log/slog"
 	"runtime/debug"
+
+	"github.com/RRWM1rr0rB/faraway_lib/backend/golang/errors"
 )
 
 // RecoverFunc handles panics during function execution.
@@ -17,7 +18,7 @@ func DefaultRecover(r any) {
 }
 
 // SafeGo runs a function in a goroutine and recovers panics.
-// Errors are sent to the returned channel.
+// Errors, including a recovered panic as *errors.PanicError, are sent to the returned channel.
 func SafeGo(ctx context.Context, fn func(context.Context) error, recoverFn RecoverFunc) <-chan error {
 	errCh := make(chan error, 1)
 	if recoverFn == nil {
@@ -28,7 +29,7 @@ func SafeGo(ctx context.Context, fn func(context.Context) error, recoverFn Recov
 		defer func() {
 			if r := recover(); r != nil {
 				recoverFn(r)
-				errCh <- fmt.Errorf("panic: %v", r)
+				errCh <- errors.FromPanic(r)
 			}
 		}()
 		if err := fn(ctx); err != nil {
@@ -38,7 +39,7 @@ func SafeGo(ctx context.Context, fn func(context.Context) error, recoverFn Recov
 	return errCh
 }
 
-// SafeFunc wraps a function with panic recovery.
+// SafeFunc wraps a function with panic recovery; a panic is returned as *errors.PanicError.
 func SafeFunc(fn func() error, recoverFn RecoverFunc) func() error {
 	if recoverFn == nil {
 		recoverFn = DefaultRecover
@@ -47,14 +48,15 @@ func SafeFunc(fn func() error, recoverFn RecoverFunc) func() error {
 		defer func() {
 			if r := recover(); r != nil {
 				recoverFn(r)
-				err = fmt.Errorf("panic: %v\n%s", r, string(debug.Stack()))
+				err = errors.FromPanic(r)
 			}
 		}()
 		return fn()
 	}
 }
 
-// SafeCtxFunc wraps a context-aware function with panic recovery.
+// SafeCtxFunc wraps a context-aware function with panic recovery; a panic is returned
+// as *errors.PanicError.
 func SafeCtxFunc(fn func(context.Context) error, recoverFn RecoverFunc) func(context.Context) error {
 	if recoverFn == nil {
 		recoverFn = DefaultRecover
@@ -63,7 +65,7 @@ func SafeCtxFunc(fn func(context.Context) error, recoverFn RecoverFunc) func(con
 		defer func() {
 			if r := recover(); r != nil {
 				recoverFn(r)
-				err = fmt.Errorf("panic: %v\n%s", r, string(debug.Stack()))
+				err = errors.FromPanic(r)
 			}
 		}()
 		return fn(ctx)
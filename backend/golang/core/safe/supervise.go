@@ -0,0 +1,87 @@
+package safe
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+)
+
+// RestartPolicy configures how Supervise restarts a failed function.
+type RestartPolicy struct {
+	MaxRestarts int           // Maximum restarts before giving up (-1 = unlimited)
+	MinBackoff  time.Duration // Delay before the first restart
+	MaxBackoff  time.Duration // Cap for the exponential backoff between restarts
+	ResetAfter  time.Duration // A run lasting this long resets the restart count and backoff (0 = never)
+}
+
+// DefaultRestartPolicy restarts up to 10 times with backoff from 100ms to 30s,
+// starting over after a run of at least a minute.
+var DefaultRestartPolicy = RestartPolicy{
+	MaxRestarts: 10,
+	MinBackoff:  100 * time.Millisecond,
+	MaxBackoff:  30 * time.Second,
+	ResetAfter:  time.Minute,
+}
+
+// Supervise runs fn and restarts it whenever it panics or returns an error,
+// waiting between restarts per the policy's exponential backoff.
+// It blocks until fn returns nil, ctx is cancelled or the restart budget is spent,
+// returning the last error in the latter cases. With unlimited restarts it keeps
+// going for as long as ctx lives.
+func Supervise(ctx context.Context, fn func(context.Context) error, policy RestartPolicy) error {
+	if policy.MinBackoff < 0 || policy.MaxBackoff < policy.MinBackoff {
+		return errors.New("safe: invalid restart backoff range")
+	}
+
+	safeFn := SafeCtxFunc(fn, nil)
+	restarts := 0
+	for {
+		started := time.Now()
+		err := safeFn(ctx)
+		if err == nil {
+			return nil
+		}
+		if ctx.Err() != nil {
+			return err
+		}
+		if policy.ResetAfter > 0 && time.Since(started) >= policy.ResetAfter {
+			restarts = 0
+		}
+		if policy.MaxRestarts >= 0 && restarts >= policy.MaxRestarts {
+			return fmt.Errorf("safe: gave up after %d restarts: %w", restarts, err)
+		}
+
+		timer := time.NewTimer(policy.backoff(restarts))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return err
+		case <-timer.C:
+		}
+
+		restarts++
+		slog.Warn("restarting supervised function",
+			"restart", restarts,
+			"max_restarts", policy.MaxRestarts,
+			"error", err,
+		)
+	}
+}
+
+// backoff returns the delay before the restart that follows n earlier ones:
+// MinBackoff doubled n times, capped at MaxBackoff.
+func (p RestartPolicy) backoff(n int) time.Duration {
+	d := p.MinBackoff
+	if d == 0 {
+		return 0
+	}
+	for range n {
+		if d > p.MaxBackoff/2 {
+			return p.MaxBackoff
+		}
+		d *= 2
+	}
+	return d
+}
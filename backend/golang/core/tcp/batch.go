@@ -0,0 +1,83 @@
+package tcp
+
+import (
+	"sync"
+	"time"
+)
+
+// writeBatcher coalesces many small writes into fewer, larger ones.
+type writeBatcher struct {
+	client   *Client
+	maxBatch int
+	maxDelay time.Duration
+
+	mu    sync.Mutex
+	buf   []byte
+	count int
+	timer *time.Timer
+}
+
+func newWriteBatcher(client *Client, maxBatch int, maxDelay time.Duration) *writeBatcher {
+	return &writeBatcher{
+		client:   client,
+		maxBatch: maxBatch,
+		maxDelay: maxDelay,
+	}
+}
+
+// add buffers data and flushes when the batch is full.
+func (b *writeBatcher) add(data []byte) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.buf = append(b.buf, data...)
+	b.count++
+
+	if b.count >= b.maxBatch {
+		return b.flushLocked()
+	}
+	if b.timer == nil {
+		b.timer = time.AfterFunc(b.maxDelay, b.flushOnTimer)
+	}
+	return nil
+}
+
+// flush sends all buffered data.
+func (b *writeBatcher) flush() error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.flushLocked()
+}
+
+// flushLocked sends all buffered data, assumes lock is already held.
+// The lock stays held during the write so batches are never reordered.
+func (b *writeBatcher) flushLocked() error {
+	if b.timer != nil {
+		b.timer.Stop()
+		b.timer = nil
+	}
+	if len(b.buf) == 0 {
+		return nil
+	}
+
+	data := b.buf
+	b.buf = nil
+	b.count = 0
+	return b.client.writeAll(data)
+}
+
+// flushOnTimer is run by the delay timer; there is no caller to return the error to.
+func (b *writeBatcher) flushOnTimer() {
+	if err := b.flush(); err != nil {
+		b.client.logger.Printf("Batched write flush failed: %v", err)
+	}
+}
+
+// Flush sends any writes buffered by WithBatchedWrites.
+// It is a no-op when batching is disabled.
+func (c *Client) Flush() error {
+	if c.batch == nil {
+		return nil
+	}
+	return c.batch.flush()
+}
@@ -5,6 +5,7 @@ import (
 	"crypto/tls"
 	"errors"
 	"fmt"
+	"io"
 	"log"
 	"net"
 	"sync"
@@ -27,7 +28,9 @@ type ConnectionStats struct {
 
 // Client represents a TCP client with connection management and statistics
 type Client struct {
-	address      string
+	address      string   // Endpoint of the current (or last) connection
+	addresses    []string // Endpoints tried in order by Connect
+	addrIndex    int      // Index in addresses of the last successful endpoint
 	conn         net.Conn
 	readTimeout  time.Duration
 	writeTimeout time.Duration
@@ -38,6 +41,12 @@ type Client struct {
 	mu           sync.RWMutex
 	ctx          context.Context    // Context for the client's lifecycle
 	cancel       context.CancelFunc // Cancel function for the client's context
+	batch        *writeBatcher      // Write coalescing, nil unless WithBatchedWrites is set
+	maxMsgSize   int                // Largest message accepted by ReadMessage and WriteMessage
+	msgRetries   int                // Reconnect attempts of the Messages loop, 0 disables
+	msgBackoff   time.Duration      // Wait before each Messages reconnect attempt
+	handshake    *Features          // Local capabilities sent on Connect, nil disables the handshake
+	features     *Features          // Result of the last handshake
 }
 
 // NewClient creates a new TCP client with the given configuration
@@ -55,9 +64,11 @@ func NewClient(
 
 	client := &Client{
 		address:      address,
+		addresses:    []string{address},
 		readTimeout:  defaultReadTimeout,
 		writeTimeout: defaultWriteTimeout,
 		bufferSize:   defaultBufferSize,
+		maxMsgSize:   defaultMaxMessageSize,
 		tlsConfig:    tlsConfig,
 		logger:       log.Default(), // Default logger
 		ctx:          ctx,
@@ -74,7 +85,33 @@ func NewClient(
 	return client, nil
 }
 
-// Connect establishes a connection to the server
+// NewClientMulti creates a TCP client that fails over between several endpoints.
+// Connect tries the addresses in order and uses the first one that accepts the connection.
+func NewClientMulti(
+	addresses []string,
+	tlsConfig *tls.Config,
+	opts ...ClientOption,
+) (*Client, error) {
+	if len(addresses) == 0 {
+		return nil, errors.New("addresses cannot be empty")
+	}
+	for _, address := range addresses {
+		if address == "" {
+			return nil, errors.New("address cannot be empty")
+		}
+	}
+
+	client, err := NewClient(addresses[0], tlsConfig, opts...)
+	if err != nil {
+		return nil, err
+	}
+	client.addresses = append([]string(nil), addresses...)
+	return client, nil
+}
+
+// Connect establishes a connection to the server.
+// With several addresses, they are tried in order starting from the last endpoint
+// that connected successfully (the first one initially), wrapping around the list.
 func (c *Client) Connect() error {
 	c.mu.RLock()
 	// Check if already connected or context cancelled while holding read lock
@@ -88,19 +125,35 @@ func (c *Client) Connect() error {
 		return &ConnectionError{Op: "connect", Err: fmt.Errorf("client context cancelled: %w", c.ctx.Err())}
 	default:
 	}
+	addresses := c.addresses
+	start := c.addrIndex
+	dialTimeout := c.writeTimeout // Use writeTimeout as connect timeout, or add a specific connect timeout option
+	local := c.handshake
 	c.mu.RUnlock()
 
 	// --- Dialing without holding the lock ---
 	var conn net.Conn
 	var err error
-	dialer := net.Dialer{Timeout: c.writeTimeout} // Use writeTimeout as connect timeout, or add a specific connect timeout option
-
-	if c.tlsConfig != nil {
-		// Pass context to DialContext for cancellable dialing
-		conn, err = tls.DialWithDialer(&dialer, TCP, c.address, c.tlsConfig)
-	} else {
-		// Pass context to DialContext for cancellable dialing
-		conn, err = dialer.DialContext(c.ctx, TCP, c.address)
+	var index int
+	var features *Features
+	for i := range addresses {
+		index = (start + i) % len(addresses)
+		conn, err = c.dial(addresses[index], dialTimeout)
+		if err == nil && local != nil {
+			// A peer that fails the handshake is treated like one that refused the connection
+			var negotiated Features
+			if negotiated, err = handshake(conn, *local, dialTimeout, true); err != nil {
+				conn.Close()
+			} else {
+				features = &negotiated
+			}
+		}
+		if err == nil || c.ctx.Err() != nil {
+			break
+		}
+		if len(addresses) > 1 {
+			c.logger.Printf("Failed to connect to %s: %v", addresses[index], err)
+		}
 	}
 
 	if err != nil {
@@ -129,6 +182,9 @@ func (c *Client) Connect() error {
 	}
 
 	c.conn = conn
+	c.features = features
+	c.address = addresses[index]
+	c.addrIndex = index
 	c.stats.LastActivity = time.Now()
 	// Reset stats for the new connection if needed (e.g., BytesRead/Written)
 	// c.stats.BytesRead = 0
@@ -137,10 +193,47 @@ func (c *Client) Connect() error {
 	return nil
 }
 
+// dial opens a connection to a single endpoint.
+func (c *Client) dial(address string, timeout time.Duration) (net.Conn, error) {
+	dialer := net.Dialer{Timeout: timeout}
+
+	if c.tlsConfig != nil {
+		return tls.DialWithDialer(&dialer, TCP, address, c.tlsConfig)
+	}
+	// Pass context to DialContext for cancellable dialing
+	return dialer.DialContext(c.ctx, TCP, address)
+}
+
+// Endpoint returns the address of the current (or last successful) connection.
+func (c *Client) Endpoint() string {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.address
+}
+
 // Read reads data from the connection
 func (c *Client) Read() ([]byte, error) {
+	conn, err := c.beginRead(context.Background())
+	if err != nil {
+		return nil, err
+	}
+
+	buf := make([]byte, c.bufferSize)
+	n, err := conn.Read(buf)
+	if err != nil {
+		return nil, c.readError(conn, err)
+	}
+
+	c.endRead(conn, n)
+	return buf[:n], nil
+}
+
+// beginRead returns the current connection with the read deadline set.
+// A read on behalf of ctx is refused once ctx is done.
+func (c *Client) beginRead(ctx context.Context) (net.Conn, error) {
 	c.mu.RLock()
 	conn := c.conn // Get current connection under read lock
+	readTimeout := c.readTimeout
 	c.mu.RUnlock() // Unlock before potentially blocking I/O
 
 	if conn == nil {
@@ -154,7 +247,7 @@ func (c *Client) Read() ([]byte, error) {
 	default:
 	}
 
-	if err := conn.SetReadDeadline(time.Now().Add(c.readTimeout)); err != nil {
+	if err := conn.SetReadDeadline(time.Now().Add(readTimeout)); err != nil {
 		// Check if the error is due to using a closed connection, which might happen
 		// if Close() was called concurrently after the nil check but before SetReadDeadline.
 		if errors.Is(err, net.ErrClosed) {
@@ -162,33 +255,41 @@ func (c *Client) Read() ([]byte, error) {
 		}
 		return nil, wrapError("set read deadline", err, false)
 	}
+	// Checked only now: cancelling ctx may have moved the deadline to interrupt the
+	// read (see Messages), and the deadline just set would have undone that.
+	if ctx.Err() != nil {
+		conn.SetReadDeadline(time.Time{})
+		return nil, &ConnectionError{Op: Read, Err: fmt.Errorf("context cancelled: %w", ctx.Err())}
+	}
 	// No need to defer reset deadline if connection might be replaced by Reconnect
 	// defer conn.SetReadDeadline(time.Time{}) // Reset deadline after read
+	return conn, nil
+}
 
-	buf := make([]byte, c.bufferSize)
-	n, err := conn.Read(buf)
-	if err != nil {
-		// Reset deadline immediately on error to avoid interfering with potential reconnect/close
-		conn.SetReadDeadline(time.Time{})
+// readError resets the read deadline and converts a failed read into a ConnectionError.
+func (c *Client) readError(conn net.Conn, err error) error {
+	// Reset deadline immediately on error to avoid interfering with potential reconnect/close
+	conn.SetReadDeadline(time.Time{})
 
-		// Check if the error is due to context cancellation (e.g., timeout triggered deadline)
-		if nerr, ok := err.(net.Error); ok && nerr.Timeout() {
-			select {
-			case <-c.ctx.Done():
-				return nil, &ConnectionError{Op: Read, Err: fmt.Errorf("context cancelled: %w", c.ctx.Err())}
-			default:
-				// It was a genuine read timeout
-				return nil, wrapError(Read, ErrTimeout, true) // Timeout is often retryable
-			}
-		}
-		// Check if the connection was closed
-		if errors.Is(err, net.ErrClosed) {
-			return nil, wrapError(Read, ErrConnectionClosed, false)
+	// Check if the error is due to context cancellation (e.g., timeout triggered deadline)
+	if nerr, ok := err.(net.Error); ok && nerr.Timeout() {
+		select {
+		case <-c.ctx.Done():
+			return &ConnectionError{Op: Read, Err: fmt.Errorf("context cancelled: %w", c.ctx.Err())}
+		default:
+			// It was a genuine read timeout
+			return wrapError(Read, ErrTimeout, true) // Timeout is often retryable
 		}
-		return nil, wrapError(Read, err, isNetworkErrorRetryable(err)) // Wrap other errors
 	}
+	// Check if the connection was closed
+	if errors.Is(err, net.ErrClosed) {
+		return wrapError(Read, ErrConnectionClosed, false)
+	}
+	return wrapError(Read, err, isNetworkErrorRetryable(err)) // Wrap other errors
+}
 
-	// Reset deadline after successful read
+// endRead resets the read deadline after a successful read of n bytes and updates the stats.
+func (c *Client) endRead(conn net.Conn, n int) {
 	conn.SetReadDeadline(time.Time{})
 
 	c.mu.Lock()
@@ -199,69 +300,139 @@ func (c *Client) Read() ([]byte, error) {
 		c.stats.LastActivity = time.Now()
 	}
 	c.mu.Unlock()
-	return buf[:n], nil
 }
 
-// Write writes data to the connection
+// Write writes data to the connection.
+// With batched writes enabled, data is buffered and sent by the next flush.
 func (c *Client) Write(data []byte) error {
+	if c.batch != nil {
+		return c.batch.add(data)
+	}
+	return c.write(data)
+}
+
+// write writes data to the connection immediately with a single write call.
+// A short write is reported as io.ErrShortWrite instead of being dropped silently.
+func (c *Client) write(data []byte) error {
+	conn, err := c.beginWrite()
+	if err != nil {
+		return err
+	}
+	n, err := c.writeOnce(conn, data)
+	if err == nil && n < len(data) {
+		return wrapError(Write, io.ErrShortWrite, false)
+	}
+	return err
+}
+
+// WriteAll writes all of data, issuing further writes after short ones until everything
+// is sent, an error occurs or the client is closed. The write timeout applies to each write.
+// With batched writes enabled, buffered writes are flushed first to keep the order.
+func (c *Client) WriteAll(data []byte) error {
+	if c.batch != nil {
+		if err := c.Flush(); err != nil {
+			return err
+		}
+	}
+	return c.writeAll(data)
+}
+
+// writeAll writes all of data to the connection immediately.
+func (c *Client) writeAll(data []byte) error {
+	conn, err := c.beginWrite()
+	if err != nil {
+		return err
+	}
+	for {
+		n, err := c.writeOnce(conn, data)
+		if err != nil {
+			return err
+		}
+		data = data[n:]
+		if len(data) == 0 {
+			return nil
+		}
+
+		select {
+		case <-c.ctx.Done():
+			return &ConnectionError{Op: Write, Err: fmt.Errorf("context cancelled: %w", c.ctx.Err())}
+		default:
+		}
+	}
+}
+
+// beginWrite returns the current connection, checking that the client is still usable.
+func (c *Client) beginWrite() (net.Conn, error) {
 	c.mu.RLock()
 	conn := c.conn // Get current connection under read lock
 	c.mu.RUnlock() // Unlock before potentially blocking I/O
 
 	if conn == nil {
-		return &ConnectionError{Op: Write, Err: ErrConnectionClosed}
+		return nil, &ConnectionError{Op: Write, Err: ErrConnectionClosed}
 	}
 
 	// Check context cancellation *before* setting deadline and writing
 	select {
 	case <-c.ctx.Done():
-		return &ConnectionError{Op: Write, Err: fmt.Errorf("context cancelled: %w", c.ctx.Err())}
+		return nil, &ConnectionError{Op: Write, Err: fmt.Errorf("context cancelled: %w", c.ctx.Err())}
 	default:
 	}
+	return conn, nil
+}
+
+// writeOnce performs one deadline-bound write and records the bytes actually written,
+// also when the write fails part way.
+func (c *Client) writeOnce(conn net.Conn, data []byte) (int, error) {
+	c.mu.RLock()
+	writeTimeout := c.writeTimeout
+	c.mu.RUnlock()
 
-	if err := conn.SetWriteDeadline(time.Now().Add(c.writeTimeout)); err != nil {
+	if err := conn.SetWriteDeadline(time.Now().Add(writeTimeout)); err != nil {
 		if errors.Is(err, net.ErrClosed) {
-			return wrapError("set write deadline", ErrConnectionClosed, false)
+			return 0, wrapError("set write deadline", ErrConnectionClosed, false)
 		}
-		return wrapError("set write deadline", err, false)
+		return 0, wrapError("set write deadline", err, false)
 	}
-	// No need to defer reset deadline
 
 	n, err := conn.Write(data)
-	if err != nil {
-		// Reset deadline immediately on error
-		conn.SetWriteDeadline(time.Time{})
+	// Reset deadline right away, on error to avoid interfering with reconnect/close
+	conn.SetWriteDeadline(time.Time{})
+
+	c.mu.Lock()
+	// Update stats only if the connection hasn't changed
+	if c.conn == conn && n > 0 {
+		c.stats.BytesWritten += uint64(n)
+		c.stats.LastActivity = time.Now()
+	}
+	c.mu.Unlock()
 
+	if err != nil {
 		// Check for timeout / context cancellation
 		if nerr, ok := err.(net.Error); ok && nerr.Timeout() {
 			select {
 			case <-c.ctx.Done():
-				return &ConnectionError{Op: Write, Err: fmt.Errorf("context cancelled: %w", c.ctx.Err())}
+				return n, &ConnectionError{Op: Write, Err: fmt.Errorf("context cancelled: %w", c.ctx.Err())}
 			default:
-				return wrapError(Write, ErrTimeout, true) // Timeout is retryable
+				return n, wrapError(Write, ErrTimeout, true) // Timeout is retryable
 			}
 		}
 		if errors.Is(err, net.ErrClosed) {
-			return wrapError(Write, ErrConnectionClosed, false)
+			return n, wrapError(Write, ErrConnectionClosed, false)
 		}
-		return wrapError(Write, err, isNetworkErrorRetryable(err)) // Wrap other errors
-	}
-
-	// Reset deadline after successful write
-	conn.SetWriteDeadline(time.Time{})
-
-	c.mu.Lock()
-	// Update stats only if the connection hasn't changed
-	if c.conn == conn {
-		c.stats.BytesWritten += uint64(n)
-		c.stats.LastActivity = time.Now()
+		return n, wrapError(Write, err, isNetworkErrorRetryable(err)) // Wrap other errors
 	}
-	c.mu.Unlock()
-	return nil
+	return n, nil
 }
 
 // Close closes the connection and cancels the client's context.
 func (c *Client) Close() error {
+	// Send whatever is still buffered before tearing the connection down
+	if c.batch != nil {
+		if err := c.Flush(); err != nil {
+			c.logger.Printf("Error flushing batched writes on close: %v", err)
+		}
+	}
+
 	c.mu.Lock() // Acquire write lock
 	// Cancel context first to signal ongoing operations (Read/Write/Connect/Retry)
 	if c.cancel != nil {
@@ -288,7 +459,37 @@ func (c *Client) Close() error {
 	return nil
 }
 
+// CloseWrite half-closes the connection: the server reads EOF while the client can
+// still read its response. Pending batched writes are flushed first.
+// For TLS connections a close_notify alert is sent instead of a TCP FIN.
+func (c *Client) CloseWrite() error {
+	if c.batch != nil {
+		if err := c.Flush(); err != nil {
+			return err
+		}
+	}
+
+	c.mu.RLock()
+	conn := c.conn
+	c.mu.RUnlock()
+
+	if conn == nil {
+		return &ConnectionError{Op: "close write", Err: ErrConnectionClosed}
+	}
+
+	cw, ok := conn.(interface{ CloseWrite() error })
+	if !ok {
+		return wrapError("close write", fmt.Errorf("half-close not supported by %T", conn), false)
+	}
+	if err := cw.CloseWrite(); err != nil {
+		return wrapError("close write", err, false)
+	}
+	return nil
+}
+
 // Reconnect closes the current connection, creates a new context, and establishes a new connection.
+// For multi-address clients the current endpoint is retried first; if it keeps failing,
+// Connect advances to the next address in the list.
 func (c *Client) Reconnect() error {
 	c.logger.Printf("Reconnect requested")
 	// Close the existing connection and cancel its associated context first.
@@ -335,6 +536,22 @@ func (c *Client) RemoteAddr() net.Addr {
 	return nil
 }
 
+// SetReadTimeout changes the read timeout of a live client.
+// It takes effect on the next Read call.
+func (c *Client) SetReadTimeout(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.readTimeout = d
+}
+
+// SetWriteTimeout changes the write timeout of a live client.
+// It takes effect on the next Write call.
+func (c *Client) SetWriteTimeout(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.writeTimeout = d
+}
+
 // Stats returns the current connection statistics.
 func (c *Client) Stats() ConnectionStats {
 	c.mu.RLock()
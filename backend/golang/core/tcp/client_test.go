@@ -0,0 +1,68 @@
+package tcp
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"net"
+	"testing"
+	"time"
+)
+
+// shortWriteConn accepts at most maxWrite bytes per Write, like a congested socket.
+type shortWriteConn struct {
+	net.Conn
+	maxWrite int
+	writes   int
+	buf      bytes.Buffer
+}
+
+func (c *shortWriteConn) Write(p []byte) (int, error) {
+	c.writes++
+	n := min(len(p), c.maxWrite)
+	c.buf.Write(p[:n])
+	return n, nil
+}
+
+func (c *shortWriteConn) SetWriteDeadline(time.Time) error { return nil }
+
+func newShortWriteClient(t *testing.T, maxWrite int) (*Client, *shortWriteConn) {
+	t.Helper()
+	client, err := NewClient("127.0.0.1:0", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	conn := &shortWriteConn{maxWrite: maxWrite}
+	client.conn = conn
+	return client, conn
+}
+
+func TestClientWriteAllLoopsOnShortWrites(t *testing.T) {
+	client, conn := newShortWriteClient(t, 3)
+	data := []byte("a message longer than one short write")
+
+	if err := client.WriteAll(data); err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(conn.buf.Bytes(), data) {
+		t.Fatalf("peer got %q, want %q", conn.buf.Bytes(), data)
+	}
+	if conn.writes < 2 {
+		t.Fatalf("expected several writes, got %d", conn.writes)
+	}
+	if got := client.Stats().BytesWritten; got != uint64(len(data)) {
+		t.Fatalf("BytesWritten = %d, want %d", got, len(data))
+	}
+}
+
+func TestClientWriteReportsShortWrite(t *testing.T) {
+	client, conn := newShortWriteClient(t, 3)
+
+	err := client.Write([]byte("too long"))
+	if !errors.Is(err, io.ErrShortWrite) {
+		t.Fatalf("got %v, want io.ErrShortWrite", err)
+	}
+	if got := client.Stats().BytesWritten; got != uint64(conn.buf.Len()) {
+		t.Fatalf("BytesWritten = %d, want the %d bytes actually written", got, conn.buf.Len())
+	}
+}
@@ -0,0 +1,119 @@
+package tcp
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"sync"
+)
+
+// envelopeHeaderSize is the length of the version byte and big-endian type ID heading
+// every typed message.
+const envelopeHeaderSize = 3
+
+// ErrUnknownMessageType is returned for typed messages with no decoder registered
+// for their version and type.
+var ErrUnknownMessageType = errors.New("unknown message type")
+
+// Envelope is a typed message: a payload tagged with its schema version and type ID,
+// so one connection can carry several message schemas and versions of each.
+type Envelope struct {
+	Version uint8
+	Type    uint16
+	Payload []byte
+}
+
+type messageKey struct {
+	version uint8
+	typeID  uint16
+}
+
+var (
+	decodersMu sync.RWMutex
+	decoders   = make(map[messageKey]func([]byte) (any, error))
+)
+
+// RegisterMessageType registers the decoder of payloads with the given version and type ID,
+// used by DecodeMessage and Client.ReadTypedMessage. Registering a new version next to the
+// old one lets a server accept both while clients upgrade.
+// Meant to be called from init; panics if decoder is nil or the version and type
+// are already registered.
+func RegisterMessageType(version uint8, typeID uint16, decoder func([]byte) (any, error)) {
+	if decoder == nil {
+		panic("tcp: RegisterMessageType decoder is nil")
+	}
+	key := messageKey{version: version, typeID: typeID}
+
+	decodersMu.Lock()
+	defer decodersMu.Unlock()
+	if _, ok := decoders[key]; ok {
+		panic(fmt.Sprintf("tcp: message type %d version %d registered twice", typeID, version))
+	}
+	decoders[key] = decoder
+}
+
+// EncodeEnvelope returns payload headed by its version byte and 2-byte big-endian type ID,
+// ready to be sent with Client.WriteMessage or WriteFrame.
+func EncodeEnvelope(version uint8, typeID uint16, payload []byte) []byte {
+	data := make([]byte, envelopeHeaderSize, envelopeHeaderSize+len(payload))
+	data[0] = version
+	binary.BigEndian.PutUint16(data[1:], typeID)
+	return append(data, payload...)
+}
+
+// ParseEnvelope splits a message produced by EncodeEnvelope into its header and payload.
+// The payload is a view into data.
+func ParseEnvelope(data []byte) (Envelope, error) {
+	if len(data) < envelopeHeaderSize {
+		return Envelope{}, fmt.Errorf("typed message of %d bytes has no header", len(data))
+	}
+	return Envelope{
+		Version: data[0],
+		Type:    binary.BigEndian.Uint16(data[1:]),
+		Payload: data[envelopeHeaderSize:],
+	}, nil
+}
+
+// DecodeMessage parses a typed message and decodes its payload with the decoder registered
+// for its version and type. Returns ErrUnknownMessageType if there is none, so the caller
+// can reject the message without dropping the connection.
+// Server handlers can use it on frames read with ReadFrame.
+func DecodeMessage(data []byte) (any, error) {
+	env, err := ParseEnvelope(data)
+	if err != nil {
+		return nil, err
+	}
+
+	decodersMu.RLock()
+	decoder, ok := decoders[messageKey{version: env.Version, typeID: env.Type}]
+	decodersMu.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("%w: type %d version %d", ErrUnknownMessageType, env.Type, env.Version)
+	}
+
+	msg, err := decoder(env.Payload)
+	if err != nil {
+		return nil, fmt.Errorf("decode message type %d version %d: %w", env.Type, env.Version, err)
+	}
+	return msg, nil
+}
+
+// WriteTypedMessage sends payload as one framed message tagged with its version and type ID.
+func (c *Client) WriteTypedMessage(version uint8, typeID uint16, payload []byte) error {
+	return c.WriteMessage(EncodeEnvelope(version, typeID, payload))
+}
+
+// ReadTypedMessage reads one framed message and decodes it with the decoder registered
+// for its version and type. Decoding errors leave the connection usable, as the whole
+// frame has been consumed.
+func (c *Client) ReadTypedMessage() (any, error) {
+	data, err := c.ReadMessage()
+	if err != nil {
+		return nil, err
+	}
+	msg, err := DecodeMessage(data)
+	if err != nil {
+		return nil, wrapError(Read, err, false)
+	}
+	return msg, nil
+}
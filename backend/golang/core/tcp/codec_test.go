@@ -0,0 +1,86 @@
+package tcp
+
+import (
+	"errors"
+	"net"
+	"strings"
+	"testing"
+)
+
+type greetingV1 struct{ Name string }
+
+type greetingV2 struct{ First, Last string }
+
+func init() {
+	RegisterMessageType(1, 100, func(b []byte) (any, error) {
+		return greetingV1{Name: string(b)}, nil
+	})
+	RegisterMessageType(2, 100, func(b []byte) (any, error) {
+		first, last, ok := strings.Cut(string(b), " ")
+		if !ok {
+			return nil, errors.New("missing last name")
+		}
+		return greetingV2{First: first, Last: last}, nil
+	})
+}
+
+func TestTypedMessagesRouteByVersion(t *testing.T) {
+	address := serveOnce(t, func(conn net.Conn) {
+		for _, m := range []struct {
+			version uint8
+			payload string
+		}{{1, "Ada Lovelace"}, {2, "Ada Lovelace"}, {2, "Ada"}, {3, "Ada"}} {
+			if err := WriteFrame(conn, EncodeEnvelope(m.version, 100, []byte(m.payload))); err != nil {
+				return
+			}
+		}
+	})
+	client := connectClient(t, address)
+
+	msg, err := client.ReadTypedMessage()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := (greetingV1{Name: "Ada Lovelace"}); msg != want {
+		t.Fatalf("got %#v, want %#v", msg, want)
+	}
+
+	msg, err = client.ReadTypedMessage()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := (greetingV2{First: "Ada", Last: "Lovelace"}); msg != want {
+		t.Fatalf("got %#v, want %#v", msg, want)
+	}
+
+	if _, err := client.ReadTypedMessage(); err == nil || errors.Is(err, ErrUnknownMessageType) {
+		t.Fatalf("got %v, want the decoder error", err)
+	}
+	// The connection stays in sync after a rejected message
+	if _, err := client.ReadTypedMessage(); !errors.Is(err, ErrUnknownMessageType) {
+		t.Fatalf("got %v, want ErrUnknownMessageType", err)
+	}
+}
+
+func TestParseEnvelope(t *testing.T) {
+	env, err := ParseEnvelope(EncodeEnvelope(7, 0x0102, []byte("body")))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if env.Version != 7 || env.Type != 0x0102 || string(env.Payload) != "body" {
+		t.Fatalf("got %+v", env)
+	}
+
+	if _, err := ParseEnvelope([]byte{1, 2}); err == nil {
+		t.Fatal("expected error for a truncated header")
+	}
+}
+
+func TestRegisterMessageTypeTwicePanics(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Fatal("expected a panic for a duplicate registration")
+		}
+	}()
+	RegisterMessageType(1, 100, func(b []byte) (any, error) { return nil, nil })
+}
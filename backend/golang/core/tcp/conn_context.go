@@ -0,0 +1,96 @@
+package tcp
+
+import (
+	"maps"
+	"net"
+	"sync"
+	"time"
+)
+
+// ConnContext describes an active server connection for CloseConnections filters.
+type ConnContext struct {
+	Conn        net.Conn
+	ConnectedAt time.Time
+	Tags        map[string]string // Copy of the tags set with Server.Tag
+}
+
+// connEntry is the registry record of a connection being handled.
+type connEntry struct {
+	conn        net.Conn
+	connectedAt time.Time
+
+	mu   sync.Mutex
+	tags map[string]string
+}
+
+// track registers a connection being handled and returns a function removing it.
+func (s *Server) track(conn net.Conn) func() {
+	s.connsMu.Lock()
+	defer s.connsMu.Unlock()
+	if s.conns == nil {
+		s.conns = make(map[net.Conn]*connEntry)
+	}
+	s.conns[conn] = &connEntry{conn: conn, connectedAt: time.Now()}
+
+	return func() {
+		s.connsMu.Lock()
+		delete(s.conns, conn)
+		s.connsMu.Unlock()
+	}
+}
+
+// Tag sets a tag on a connection being handled, e.g. Tag(conn, "tenant", "acme") from the handler.
+// Returns false if the connection is not (or no longer) handled by this server.
+func (s *Server) Tag(conn net.Conn, key, value string) bool {
+	if nc, ok := conn.(*negotiatedConn); ok {
+		conn = nc.Conn
+	}
+
+	s.connsMu.Lock()
+	entry, ok := s.conns[conn]
+	s.connsMu.Unlock()
+	if !ok {
+		return false
+	}
+
+	entry.mu.Lock()
+	defer entry.mu.Unlock()
+	if entry.tags == nil {
+		entry.tags = make(map[string]string)
+	}
+	entry.tags[key] = value
+	return true
+}
+
+// CloseConnections closes every active connection for which filter returns true,
+// e.g. all connections of one tenant, and returns how many were closed.
+// Handlers see the close as a read or write error and finish as usual.
+func (s *Server) CloseConnections(filter func(ConnContext) bool) int {
+	s.connsMu.Lock()
+	entries := make([]*connEntry, 0, len(s.conns))
+	for _, entry := range s.conns {
+		entries = append(entries, entry)
+	}
+	s.connsMu.Unlock()
+
+	closed := 0
+	for _, entry := range entries {
+		entry.mu.Lock()
+		cc := ConnContext{
+			Conn:        entry.conn,
+			ConnectedAt: entry.connectedAt,
+			Tags:        maps.Clone(entry.tags),
+		}
+		entry.mu.Unlock()
+
+		if !filter(cc) {
+			continue
+		}
+		if err := entry.conn.Close(); err != nil {
+			s.logger.Printf("Error closing connection from %s: %v", entry.conn.RemoteAddr(), err)
+			continue
+		}
+		closed++
+	}
+	return closed
+}
@@ -0,0 +1,131 @@
+package tcp
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+)
+
+const (
+	// frameHeaderSize is the length of the big-endian size prefix of a frame.
+	frameHeaderSize = 4
+	// defaultMaxMessageSize is the default limit of ReadMessage and WriteMessage.
+	defaultMaxMessageSize = 4 << 20
+)
+
+// ErrMessageTooLarge is returned when a frame exceeds the configured maximum message size.
+var ErrMessageTooLarge = errors.New("message exceeds maximum size")
+
+// ReadFrame reads one length-prefixed frame from r: a 4-byte big-endian length followed
+// by that many bytes of payload. A length above maxSize is rejected with ErrMessageTooLarge
+// before anything is allocated; the payload is then left unread, so the stream is out of
+// sync and the connection should be closed. maxSize <= 0 disables the limit.
+// Server handlers can use it to read messages sent with Client.WriteMessage.
+func ReadFrame(r io.Reader, maxSize int) ([]byte, error) {
+	var header [frameHeaderSize]byte
+	if _, err := io.ReadFull(r, header[:]); err != nil {
+		return nil, err
+	}
+	size := binary.BigEndian.Uint32(header[:])
+	if maxSize > 0 && uint64(size) > uint64(maxSize) {
+		return nil, fmt.Errorf("%w: %d > %d bytes", ErrMessageTooLarge, size, maxSize)
+	}
+
+	data := make([]byte, size)
+	if _, err := io.ReadFull(r, data); err != nil {
+		if errors.Is(err, io.EOF) {
+			err = io.ErrUnexpectedEOF // The header promised a payload
+		}
+		return nil, err
+	}
+	return data, nil
+}
+
+// WriteFrame writes data to w as one length-prefixed frame in a single write.
+func WriteFrame(w io.Writer, data []byte) error {
+	frame, err := appendFrame(nil, data)
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(frame)
+	return err
+}
+
+// appendFrame appends data with its length prefix to dst.
+func appendFrame(dst, data []byte) ([]byte, error) {
+	if uint64(len(data)) > uint64(^uint32(0)) {
+		return nil, fmt.Errorf("%w: %d bytes does not fit a frame", ErrMessageTooLarge, len(data))
+	}
+	if dst == nil {
+		dst = make([]byte, 0, frameHeaderSize+len(data))
+	}
+	dst = binary.BigEndian.AppendUint32(dst, uint32(len(data)))
+	return append(dst, data...), nil
+}
+
+// ReadMessage reads one message written by WriteMessage, waiting until the whole frame
+// has arrived however TCP fragments it. The read timeout applies to the whole message;
+// only a timeout before any byte of it arrived is reported as the retryable ErrTimeout.
+// Messages larger than the WithMaxMessageSize limit are rejected with ErrMessageTooLarge
+// without allocating them; the connection is unusable afterwards and should be reconnected.
+func (c *Client) ReadMessage() ([]byte, error) {
+	return c.readMessage(context.Background())
+}
+
+// readMessage is ReadMessage on behalf of ctx, see beginRead.
+func (c *Client) readMessage(ctx context.Context) ([]byte, error) {
+	conn, err := c.beginRead(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	cr := &countingReader{r: conn}
+	data, err := ReadFrame(cr, c.maxMsgSize)
+	if err != nil {
+		if errors.Is(err, ErrMessageTooLarge) {
+			conn.SetReadDeadline(time.Time{})
+			return nil, wrapError(Read, err, false)
+		}
+		if cr.n > 0 {
+			// Part of the frame is consumed, so even a timeout leaves the stream out of sync
+			conn.SetReadDeadline(time.Time{})
+			return nil, wrapError(Read, fmt.Errorf("message cut off after %d bytes: %w", cr.n, err), false)
+		}
+		return nil, c.readError(conn, err)
+	}
+
+	c.endRead(conn, frameHeaderSize+len(data))
+	return data, nil
+}
+
+// countingReader counts the bytes read through it.
+type countingReader struct {
+	r io.Reader
+	n int
+}
+
+func (cr *countingReader) Read(p []byte) (int, error) {
+	n, err := cr.r.Read(p)
+	cr.n += n
+	return n, err
+}
+
+// WriteMessage writes data as one length-prefixed frame: a 4-byte big-endian length
+// followed by the payload. Data larger than the WithMaxMessageSize limit is rejected
+// with ErrMessageTooLarge, since the peer would refuse it anyway.
+func (c *Client) WriteMessage(data []byte) error {
+	if c.maxMsgSize > 0 && len(data) > c.maxMsgSize {
+		return wrapError(Write, fmt.Errorf("%w: %d > %d bytes", ErrMessageTooLarge, len(data), c.maxMsgSize), false)
+	}
+	frame, err := appendFrame(nil, data)
+	if err != nil {
+		return wrapError(Write, err, false)
+	}
+	if c.batch != nil {
+		return c.batch.add(frame)
+	}
+	return c.writeAll(frame)
+}
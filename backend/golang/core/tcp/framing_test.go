@@ -0,0 +1,104 @@
+package tcp
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"net"
+	"testing"
+	"time"
+)
+
+// serveOnce accepts a single connection on a local listener and passes it to fn.
+func serveOnce(t *testing.T, fn func(net.Conn)) string {
+	t.Helper()
+	ln, err := net.Listen(TCP, "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { ln.Close() })
+	go func() {
+		conn, err := ln.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		fn(conn)
+	}()
+	return ln.Addr().String()
+}
+
+func connectClient(t *testing.T, address string, opts ...ClientOption) *Client {
+	t.Helper()
+	client, err := NewClient(address, nil, append([]ClientOption{WithTimeouts(2*time.Second, 2*time.Second)}, opts...)...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := client.Connect(); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { client.Close() })
+	return client
+}
+
+func TestReadMessageAcrossSegments(t *testing.T) {
+	payload := bytes.Repeat([]byte("segment"), 100)
+	frame, err := appendFrame(nil, payload)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	address := serveOnce(t, func(conn net.Conn) {
+		// Dribble the frame out so the header and body arrive in many TCP segments
+		for i := 0; i < len(frame); i += 50 {
+			conn.Write(frame[i:min(i+50, len(frame))])
+			time.Sleep(time.Millisecond)
+		}
+		time.Sleep(100 * time.Millisecond)
+	})
+	client := connectClient(t, address)
+
+	got, err := client.ReadMessage()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(got, payload) {
+		t.Fatalf("got %d bytes, want %d", len(got), len(payload))
+	}
+}
+
+func TestReadMessageRejectsOversizedLength(t *testing.T) {
+	address := serveOnce(t, func(conn net.Conn) {
+		var header [frameHeaderSize]byte
+		binary.BigEndian.PutUint32(header[:], 1<<31) // Claims 2 GiB
+		conn.Write(header[:])
+		time.Sleep(100 * time.Millisecond)
+	})
+	client := connectClient(t, address, WithMaxMessageSize(1024))
+
+	if _, err := client.ReadMessage(); !errors.Is(err, ErrMessageTooLarge) {
+		t.Fatalf("got %v, want ErrMessageTooLarge", err)
+	}
+}
+
+func TestWriteMessageFramesPayload(t *testing.T) {
+	received := make(chan []byte, 1)
+	address := serveOnce(t, func(conn net.Conn) {
+		data, err := ReadFrame(conn, 1024)
+		if err != nil {
+			t.Error(err)
+		}
+		received <- data
+	})
+	client := connectClient(t, address, WithMaxMessageSize(8))
+
+	if err := client.WriteMessage([]byte("too large")); !errors.Is(err, ErrMessageTooLarge) {
+		t.Fatalf("got %v, want ErrMessageTooLarge", err)
+	}
+	if err := client.WriteMessage([]byte("hello")); err != nil {
+		t.Fatal(err)
+	}
+	if got := <-received; string(got) != "hello" {
+		t.Fatalf("server got %q, want %q", got, "hello")
+	}
+}
@@ -0,0 +1,108 @@
+package tcp
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"slices"
+	"time"
+)
+
+// maxHandshakeSize caps the capabilities frame so a bogus length prefix can't force a large allocation.
+const maxHandshakeSize = 4096
+
+// ErrHandshake is returned when the capabilities exchange fails or the peers have nothing in common.
+var ErrHandshake = errors.New("protocol handshake failed")
+
+// Features describes the protocol capabilities a peer supports, or after negotiation,
+// the capabilities both peers agreed on.
+type Features struct {
+	Version        uint16   `json:"version"`          // Highest supported protocol version
+	Compression    []string `json:"compression"`      // Supported algorithms in order of preference
+	MaxMessageSize uint32   `json:"max_message_size"` // Largest accepted message, 0 means no limit
+}
+
+// negotiate returns the intersection of local and peer capabilities:
+// the lower version, the common compression algorithms in the dialing client's
+// preference order and the smaller non-zero message size limit. Ordering by the
+// client on both ends makes both peers agree on Compression[0].
+func negotiate(local, peer Features, isClient bool) (Features, error) {
+	if local.Version == 0 || peer.Version == 0 {
+		return Features{}, fmt.Errorf("%w: protocol version must be positive", ErrHandshake)
+	}
+
+	result := Features{
+		Version:        min(local.Version, peer.Version),
+		Compression:    make([]string, 0),
+		MaxMessageSize: local.MaxMessageSize,
+	}
+	preferred, other := local.Compression, peer.Compression
+	if !isClient {
+		preferred, other = other, preferred
+	}
+	for _, algo := range preferred {
+		if slices.Contains(other, algo) {
+			result.Compression = append(result.Compression, algo)
+		}
+	}
+	if peer.MaxMessageSize != 0 && (result.MaxMessageSize == 0 || peer.MaxMessageSize < result.MaxMessageSize) {
+		result.MaxMessageSize = peer.MaxMessageSize
+	}
+	return result, nil
+}
+
+// handshake sends local capabilities as a length-prefixed JSON frame, reads the peer's
+// and negotiates the result. Both sides write first, so the exchange is symmetric;
+// isClient only tells whose compression preferences win.
+func handshake(conn net.Conn, local Features, timeout time.Duration, isClient bool) (Features, error) {
+	if err := conn.SetDeadline(time.Now().Add(timeout)); err != nil {
+		return Features{}, fmt.Errorf("%w: %w", ErrHandshake, err)
+	}
+	defer conn.SetDeadline(time.Time{})
+
+	body, err := json.Marshal(local)
+	if err != nil {
+		return Features{}, fmt.Errorf("%w: %w", ErrHandshake, err)
+	}
+	if err := WriteFrame(conn, body); err != nil {
+		return Features{}, fmt.Errorf("%w: write capabilities: %w", ErrHandshake, err)
+	}
+
+	body, err = ReadFrame(conn, maxHandshakeSize)
+	if err != nil {
+		return Features{}, fmt.Errorf("%w: read capabilities: %w", ErrHandshake, err)
+	}
+
+	var peer Features
+	if err := json.Unmarshal(body, &peer); err != nil {
+		return Features{}, fmt.Errorf("%w: decode capabilities: %w", ErrHandshake, err)
+	}
+	return negotiate(local, peer, isClient)
+}
+
+// negotiatedConn carries the features negotiated by the Server handshake to the handler.
+type negotiatedConn struct {
+	net.Conn
+	features Features
+}
+
+// ConnFeatures returns the features negotiated for a connection passed to a Server handler,
+// and false if the Server has no handshake configured.
+func ConnFeatures(conn net.Conn) (Features, bool) {
+	if nc, ok := conn.(*negotiatedConn); ok {
+		return nc.features, true
+	}
+	return Features{}, false
+}
+
+// NegotiatedFeatures returns the features agreed on during the last Connect,
+// and false if no handshake is configured or the client is not connected.
+func (c *Client) NegotiatedFeatures() (Features, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	if c.features == nil || c.conn == nil {
+		return Features{}, false
+	}
+	return *c.features, true
+}
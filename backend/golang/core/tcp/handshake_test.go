@@ -0,0 +1,55 @@
+package tcp
+
+import (
+	"io"
+	"log"
+	"net"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestHandshakeAgreesOnCompressionOrder(t *testing.T) {
+	serverFeatures := make(chan Features, 1)
+	handler := func(conn net.Conn) {
+		features, _ := ConnFeatures(conn)
+		serverFeatures <- features
+	}
+	server, err := NewServer("127.0.0.1:0", handler, nil,
+		WithServerLogger(log.New(io.Discard, "", 0)),
+		WithServerHandshake(Features{Version: 2, Compression: []string{"gzip", "zstd", "lz4"}, MaxMessageSize: 1 << 20}),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := server.Start(); err != nil {
+		t.Fatal(err)
+	}
+	defer server.Stop()
+	server.mu.RLock()
+	address := server.listener.Addr().String()
+	server.mu.RUnlock()
+
+	client := connectClient(t, address,
+		WithHandshake(Features{Version: 1, Compression: []string{"snappy", "zstd", "gzip"}, MaxMessageSize: 4 << 20}),
+	)
+	clientSide, ok := client.NegotiatedFeatures()
+	if !ok {
+		t.Fatal("client has no negotiated features")
+	}
+
+	var serverSide Features
+	select {
+	case serverSide = <-serverFeatures:
+	case <-time.After(2 * time.Second):
+		t.Fatal("server handler not called")
+	}
+
+	want := Features{Version: 1, Compression: []string{"zstd", "gzip"}, MaxMessageSize: 1 << 20}
+	if !reflect.DeepEqual(clientSide, want) {
+		t.Errorf("client negotiated %+v, want %+v", clientSide, want)
+	}
+	if !reflect.DeepEqual(serverSide, clientSide) {
+		t.Errorf("server negotiated %+v, client %+v", serverSide, clientSide)
+	}
+}
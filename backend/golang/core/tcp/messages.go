@@ -0,0 +1,113 @@
+package tcp
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// messagesBuffer is the capacity of the channel returned by Messages.
+const messagesBuffer = 16
+
+// Messages runs a background read loop and delivers each message, as framed by
+// WriteMessage on the peer, on the returned channel.
+// The loop stops when ctx is cancelled or a read fails for good; both channels are then closed,
+// with the terminating error (if any) sent on the error channel first.
+// Cancelling ctx interrupts a pending read at once; a message cut off that way is lost
+// and leaves the stream out of sync, so reconnect before reading from the Client again.
+// A message already read when ctx is cancelled is still delivered if the channel has room.
+// Read timeouts are not fatal, the loop simply keeps waiting for data.
+// With WithMessagesReconnect, a broken connection is reconnected between messages
+// before the loop gives up.
+func (c *Client) Messages(ctx context.Context) (<-chan []byte, <-chan error) {
+	msgs := make(chan []byte, messagesBuffer)
+	errs := make(chan error, 1)
+
+	go func() {
+		defer close(msgs)
+		defer close(errs)
+
+		stop := context.AfterFunc(ctx, c.interruptRead)
+		defer stop()
+
+		for {
+			if ctx.Err() != nil {
+				return
+			}
+
+			data, err := c.readMessage(ctx)
+			if err != nil {
+				if ctx.Err() != nil {
+					return
+				}
+				if errors.Is(err, ErrTimeout) {
+					continue
+				}
+				if rerr := c.reconnectMessages(ctx, err); rerr != nil {
+					if ctx.Err() == nil {
+						errs <- rerr
+					}
+					return
+				}
+				continue
+			}
+
+			select {
+			case msgs <- data:
+				continue
+			default:
+			}
+			select {
+			case msgs <- data:
+			case <-ctx.Done():
+				c.logger.Printf("Message loop cancelled, dropping a %d-byte message the consumer did not take", len(data))
+				return
+			}
+		}
+	}()
+
+	return msgs, errs
+}
+
+// interruptRead makes a pending read on the current connection return at once.
+func (c *Client) interruptRead() {
+	c.mu.RLock()
+	conn := c.conn
+	c.mu.RUnlock()
+	if conn != nil {
+		conn.SetReadDeadline(time.Now())
+	}
+}
+
+// reconnectMessages tries to recover the read loop after readErr using the
+// WithMessagesReconnect settings. Returns nil once reconnected.
+func (c *Client) reconnectMessages(ctx context.Context, readErr error) error {
+	if c.msgRetries <= 0 {
+		return readErr
+	}
+
+	var lastErr error
+	for i := 0; i < c.msgRetries; i++ {
+		c.logger.Printf("Message loop read failed: %v. Reconnecting in %v (%d/%d)...", readErr, c.msgBackoff, i+1, c.msgRetries)
+		c.mu.Lock()
+		c.stats.RetryCount++
+		c.mu.Unlock()
+
+		select {
+		case <-time.After(c.msgBackoff):
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+
+		if lastErr = c.Reconnect(); lastErr == nil {
+			c.logger.Printf("Reconnect successful.")
+			c.mu.Lock()
+			c.stats.RetryCount = 0
+			c.mu.Unlock()
+			return nil
+		}
+		c.logger.Printf("Reconnect failed: %v", lastErr)
+	}
+	return fmt.Errorf("reconnect after read failed: %w (original read error: %v)", lastErr, readErr)
+}
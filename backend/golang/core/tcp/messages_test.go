@@ -0,0 +1,67 @@
+package tcp
+
+import (
+	"bytes"
+	"context"
+	"net"
+	"testing"
+	"time"
+)
+
+func TestMessagesCancelInterruptsIdleRead(t *testing.T) {
+	done := make(chan struct{})
+	t.Cleanup(func() { close(done) })
+	address := serveOnce(t, func(net.Conn) { <-done })
+	client := connectClient(t, address, WithTimeouts(time.Minute, time.Minute))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	msgs, errs := client.Messages(ctx)
+	time.Sleep(50 * time.Millisecond) // let the loop block in a read
+	cancel()
+
+	select {
+	case _, ok := <-msgs:
+		if ok {
+			t.Fatal("got a message, want the channel closed")
+		}
+	case <-time.After(time.Second):
+		t.Fatal("message loop still reading after cancel")
+	}
+	if err, ok := <-errs; ok {
+		t.Fatalf("got error %v after cancel, want the channel closed", err)
+	}
+}
+
+func TestMessagesReportErrorBeforeClosing(t *testing.T) {
+	payloads := [][]byte{[]byte("first"), []byte("second")}
+	address := serveOnce(t, func(conn net.Conn) {
+		for _, p := range payloads {
+			if err := WriteFrame(conn, p); err != nil {
+				return
+			}
+		}
+	})
+	client := connectClient(t, address)
+
+	msgs, errs := client.Messages(context.Background())
+	var got [][]byte
+	for msg := range msgs {
+		got = append(got, msg)
+	}
+	if len(got) != len(payloads) || !bytes.Equal(got[0], payloads[0]) || !bytes.Equal(got[1], payloads[1]) {
+		t.Fatalf("got %q, want %q", got, payloads)
+	}
+
+	// The error is in place by the time the message channel is closed
+	select {
+	case err, ok := <-errs:
+		if !ok || err == nil {
+			t.Fatalf("got %v, %v from the error channel, want the read error", err, ok)
+		}
+	default:
+		t.Fatal("message channel closed before the error was sent")
+	}
+	if _, ok := <-errs; ok {
+		t.Fatal("error channel not closed after the error")
+	}
+}
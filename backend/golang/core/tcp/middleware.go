@@ -6,6 +6,7 @@ import (
 	"io"
 	"log"
 	"net"
+	"slices"
 	"sync"
 	"time"
 )
@@ -28,6 +29,13 @@ type RateLimiter struct {
 	difficulties map[string]int32
 	logger       *log.Logger
 
+	// Concurrent connection limit per IP (0 = unlimited).
+	maxConcurrentPerIP int64
+	// IP -> currently open connections.
+	activePerIP map[string]int64
+	// Connection -> IP, for connections counted in activePerIP.
+	activeConns map[net.Conn]string
+
 	// For background cleanup
 	stopCh chan struct{}
 	wg     sync.WaitGroup
@@ -38,8 +46,19 @@ type rateCounter struct {
 	timestamp time.Time
 }
 
+// RateLimiterOption defines functional options for configuring the RateLimiter.
+type RateLimiterOption func(*RateLimiter)
+
+// WithMaxConcurrentPerIP caps the number of simultaneously open connections per IP.
+// A Server releases connections as it closes them; see Release for other uses.
+func WithMaxConcurrentPerIP(n int64) RateLimiterOption {
+	return func(r *RateLimiter) {
+		r.maxConcurrentPerIP = n
+	}
+}
+
 // NewRateLimiter create new RateLimiter and starts background cleanup.
-func NewRateLimiter(logger *log.Logger) *RateLimiter {
+func NewRateLimiter(logger *log.Logger, opts ...RateLimiterOption) *RateLimiter {
 	if logger == nil {
 		logger = log.New(io.Discard, "[RateLimiter] ", log.LstdFlags) // Use io.Discard or provide a default logger
 	}
@@ -47,10 +66,16 @@ func NewRateLimiter(logger *log.Logger) *RateLimiter {
 		connectionsPerIP: make(map[string]*rateCounter),
 		bannedIPs:        make(map[string]time.Time),
 		difficulties:     make(map[string]int32),
+		activePerIP:      make(map[string]int64),
+		activeConns:      make(map[net.Conn]string),
 		logger:           logger,
 		stopCh:           make(chan struct{}),
 	}
 
+	for _, opt := range opts {
+		opt(rl)
+	}
+
 	rl.wg.Add(1)
 	go rl.cleanupLoop()
 
@@ -165,16 +190,95 @@ func RateLimitMiddleware(limiter *RateLimiter) func(conn net.Conn) bool {
 			// limiter.decreaseDifficulty(ip) // Optional: give benefit for solving hard puzzle
 			limiter.logger.Printf("IP %s: Valid PoW solution received (difficulty %d)", ip, difficulty)
 			// PoW passed, allow connection to proceed
-			return true
+			return limiter.acquire(conn, ip)
 
 		}
 
 		// 3. If rate limit is okay, proceed directly without PoW
+		if !limiter.acquire(conn, ip) {
+			return false
+		}
 		limiter.logger.Printf("IP %s accepted (within rate limit)", ip)
 		return true
 	}
 }
 
+// acquire counts conn against the per-IP concurrency limit.
+// It CLOSES the connection and returns false if the limit is reached.
+func (r *RateLimiter) acquire(conn net.Conn, ip string) bool {
+	if r.maxConcurrentPerIP <= 0 {
+		return true
+	}
+
+	r.mu.Lock()
+	if r.activePerIP[ip] >= r.maxConcurrentPerIP {
+		r.mu.Unlock()
+		r.logger.Printf("IP %s rejected: %d concurrent connections open", ip, r.maxConcurrentPerIP)
+		conn.Close()
+		return false
+	}
+	r.activePerIP[ip]++
+	r.activeConns[conn] = ip
+	r.mu.Unlock()
+
+	connLimitersMu.Lock()
+	connLimiters[conn] = append(connLimiters[conn], r)
+	connLimitersMu.Unlock()
+	return true
+}
+
+// Release stops counting conn against the per-IP concurrency limit.
+// A Server does this itself when it closes a connection, however the middleware was
+// installed; call Release only when applying RateLimitMiddleware outside a Server.
+// Calling it for connections that weren't counted is a no-op.
+func (r *RateLimiter) Release(conn net.Conn) {
+	connLimitersMu.Lock()
+	if limiters := slices.DeleteFunc(connLimiters[conn], func(l *RateLimiter) bool { return l == r }); len(limiters) > 0 {
+		connLimiters[conn] = limiters
+	} else {
+		delete(connLimiters, conn)
+	}
+	connLimitersMu.Unlock()
+
+	r.release(conn)
+}
+
+// release drops conn from the per-IP concurrency count.
+func (r *RateLimiter) release(conn net.Conn) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	ip, ok := r.activeConns[conn]
+	if !ok {
+		return
+	}
+	delete(r.activeConns, conn)
+	r.activePerIP[ip]--
+	if r.activePerIP[ip] <= 0 {
+		delete(r.activePerIP, ip)
+	}
+}
+
+// connLimiters maps each connection counted by a RateLimiter to the limiters counting it,
+// so the Server can release it on close without knowing which middleware is installed.
+var (
+	connLimitersMu sync.Mutex
+	connLimiters   = make(map[net.Conn][]*RateLimiter)
+)
+
+// releaseConn releases conn from every RateLimiter counting it. The Server calls it
+// once a connection is closed.
+func releaseConn(conn net.Conn) {
+	connLimitersMu.Lock()
+	limiters := connLimiters[conn]
+	delete(connLimiters, conn)
+	connLimitersMu.Unlock()
+
+	for _, r := range limiters {
+		r.release(conn)
+	}
+}
+
 // Helper function to apply middleware in server handleConnection
 func ApplyMiddleware(conn net.Conn, middleware func(net.Conn) bool, handler func(net.Conn)) {
 	if middleware != nil {
@@ -0,0 +1,172 @@
+package tcp
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"net"
+	"sync"
+)
+
+// muxIDSize is the length of the big-endian correlation ID heading every mux frame.
+const muxIDSize = 8
+
+// ErrMuxClosed is returned for requests on a closed MuxClient or pending when it closed.
+var ErrMuxClosed = errors.New("mux client closed")
+
+// MuxClient issues concurrent requests over a single Client connection and matches
+// responses to callers by correlation ID, so responses may arrive in any order.
+// Each request and response is one WriteMessage frame whose payload starts with the
+// 8-byte big-endian ID; MuxHandler implements the server side.
+type MuxClient struct {
+	client *Client
+
+	mu      sync.Mutex
+	nextID  uint64
+	pending map[uint64]chan muxResult
+	err     error // Set once the read loop stops, fails all later requests
+	done    chan struct{}
+}
+
+type muxResult struct {
+	data []byte
+	err  error
+}
+
+// NewMuxClient starts multiplexing over a connected client.
+// The MuxClient owns the client's reads from now on; Close closes the client too.
+func NewMuxClient(client *Client) *MuxClient {
+	m := &MuxClient{
+		client:  client,
+		pending: make(map[uint64]chan muxResult),
+		done:    make(chan struct{}),
+	}
+	go m.readLoop()
+	return m
+}
+
+// Request sends data and waits for the matching response or ctx cancellation.
+// Safe for concurrent use.
+func (m *MuxClient) Request(ctx context.Context, data []byte) ([]byte, error) {
+	ch := make(chan muxResult, 1)
+
+	m.mu.Lock()
+	if m.err != nil {
+		err := m.err
+		m.mu.Unlock()
+		return nil, err
+	}
+	m.nextID++
+	id := m.nextID
+	m.pending[id] = ch
+	m.mu.Unlock()
+
+	frame := make([]byte, muxIDSize, muxIDSize+len(data))
+	binary.BigEndian.PutUint64(frame, id)
+	if err := m.client.WriteMessage(append(frame, data...)); err != nil {
+		m.forget(id)
+		return nil, err
+	}
+
+	select {
+	case res := <-ch:
+		return res.data, res.err
+	case <-ctx.Done():
+		m.forget(id)
+		return nil, ctx.Err()
+	}
+}
+
+// Close closes the underlying client and fails all pending requests with ErrMuxClosed.
+func (m *MuxClient) Close() error {
+	m.fail(ErrMuxClosed)
+	err := m.client.Close()
+	<-m.done
+	return err
+}
+
+// forget drops a pending request whose caller stopped waiting.
+func (m *MuxClient) forget(id uint64) {
+	m.mu.Lock()
+	delete(m.pending, id)
+	m.mu.Unlock()
+}
+
+// fail stops accepting requests and completes all pending ones with err.
+// Only the first call has an effect.
+func (m *MuxClient) fail(err error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.err != nil {
+		return
+	}
+	m.err = err
+	for id, ch := range m.pending {
+		ch <- muxResult{err: err}
+		delete(m.pending, id)
+	}
+}
+
+// readLoop routes responses to pending requests until the connection fails.
+func (m *MuxClient) readLoop() {
+	defer close(m.done)
+	for {
+		msg, err := m.client.ReadMessage()
+		if err != nil {
+			if errors.Is(err, ErrTimeout) {
+				continue
+			}
+			m.fail(err)
+			return
+		}
+		if len(msg) < muxIDSize {
+			m.fail(wrapError(Read, fmt.Errorf("mux frame of %d bytes has no correlation ID", len(msg)), false))
+			return
+		}
+
+		id := binary.BigEndian.Uint64(msg)
+		m.mu.Lock()
+		ch, ok := m.pending[id]
+		delete(m.pending, id)
+		m.mu.Unlock()
+		// Responses to requests abandoned by their caller are dropped
+		if ok {
+			ch <- muxResult{data: msg[muxIDSize:]}
+		}
+	}
+}
+
+// MuxHandler returns a Server handler answering MuxClient requests. Each request is
+// handled on its own goroutine and the response is written with the request's ID,
+// so slow requests don't hold up others on the same connection.
+// Requests larger than maxSize are rejected by closing the connection; maxSize <= 0
+// disables the limit.
+func MuxHandler(handle func(req []byte) []byte, maxSize int) func(net.Conn) {
+	return func(conn net.Conn) {
+		defer conn.Close()
+
+		var (
+			wg      sync.WaitGroup
+			writeMu sync.Mutex
+		)
+		defer wg.Wait()
+
+		for {
+			msg, err := ReadFrame(conn, maxSize)
+			if err != nil || len(msg) < muxIDSize {
+				return
+			}
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				resp := handle(msg[muxIDSize:])
+				frame := append(msg[:muxIDSize:muxIDSize], resp...)
+
+				writeMu.Lock()
+				defer writeMu.Unlock()
+				WriteFrame(conn, frame)
+			}()
+		}
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"crypto/tls"
 	"log"
 	"net"
+	"strings"
 	"time"
 )
 
@@ -42,6 +43,45 @@ func WithTLSClientConfig(config *tls.Config) ClientOption {
 	}
 }
 
+// WithBatchedWrites enables write coalescing for the Client.
+// Writes are buffered and sent as a single write once maxBatch writes are pending
+// or maxDelay has passed since the first buffered write, whichever comes first.
+func WithBatchedWrites(maxBatch int, maxDelay time.Duration) ClientOption {
+	return func(c *Client) {
+		if maxBatch <= 0 || maxDelay <= 0 {
+			return
+		}
+		c.batch = newWriteBatcher(c, maxBatch, maxDelay)
+	}
+}
+
+// WithMaxMessageSize sets the largest message ReadMessage accepts and WriteMessage sends,
+// so a bogus length prefix can't force a huge allocation. n <= 0 disables the limit.
+// The default is 4 MiB.
+func WithMaxMessageSize(n int) ClientOption {
+	return func(c *Client) {
+		c.maxMsgSize = n
+	}
+}
+
+// WithHandshake makes Connect exchange capabilities with the server right after dialing.
+// The server must be configured with WithServerHandshake; the agreed features are
+// available from Client.NegotiatedFeatures.
+func WithHandshake(local Features) ClientOption {
+	return func(c *Client) {
+		c.handshake = &local
+	}
+}
+
+// WithMessagesReconnect makes the Client.Messages read loop reconnect after a broken
+// connection, trying up to maxRetries times with backoff between attempts.
+func WithMessagesReconnect(maxRetries int, backoff time.Duration) ClientOption {
+	return func(c *Client) {
+		c.msgRetries = maxRetries
+		c.msgBackoff = backoff
+	}
+}
+
 // WithServerTimeout sets the idle timeout for the Server.
 func WithServerTimeout(timeout time.Duration) ServerOption {
 	return func(s *Server) {
@@ -63,6 +103,66 @@ func WithServerTLS(config *tls.Config) ServerOption {
 	}
 }
 
+// WithServerHandshake makes the Server exchange capabilities with each client before
+// calling the handler, which can read the agreed features with ConnFeatures.
+// Clients that fail the handshake are disconnected.
+func WithServerHandshake(local Features) ServerOption {
+	return func(s *Server) {
+		s.handshake = &local
+	}
+}
+
+// WithIdempotentLifecycle makes Start on a running Server and Stop on a stopped one
+// return nil instead of an error, which suits supervisors that restart blindly.
+func WithIdempotentLifecycle() ServerOption {
+	return func(s *Server) {
+		s.idempotent = true
+	}
+}
+
+// WithSNIRouter routes TLS connections to a handler chosen by the SNI hostname
+// the client sent; unknown or missing names fall back to the Server handler.
+// Hostnames are matched case-insensitively. Has no effect without a TLS config.
+func WithSNIRouter(routes map[string]func(net.Conn)) ServerOption {
+	return func(s *Server) {
+		s.sniRoutes = make(map[string]func(net.Conn), len(routes))
+		for name, handler := range routes {
+			s.sniRoutes[strings.ToLower(name)] = handler
+		}
+	}
+}
+
+// WithWorkerPool handles connections on a fixed number of goroutines instead of one per
+// connection, decoupling connection count from compute concurrency for CPU-bound handlers.
+// While all workers are busy, the server stops accepting and new connections wait in the
+// kernel backlog.
+func WithWorkerPool(size int) ServerOption {
+	return func(s *Server) {
+		s.workers = size
+	}
+}
+
+// WithAcceptBackoff sets how long the Server waits after a failed accept before retrying:
+// minWait after the first failure, doubling on each consecutive one up to maxWait.
+// Defaults to 5ms and 1s.
+func WithAcceptBackoff(minWait, maxWait time.Duration) ServerOption {
+	return func(s *Server) {
+		if minWait <= 0 || maxWait < minWait {
+			return
+		}
+		s.acceptBackoffMin = minWait
+		s.acceptBackoffMax = maxWait
+	}
+}
+
+// WithReusePort makes the Server listen with SO_REUSEPORT, letting several processes
+// bind the same address while the kernel balances accepts between them.
+func WithReusePort() ServerOption {
+	return func(s *Server) {
+		s.reusePort = true
+	}
+}
+
 // WithPoolLogger Option to set logger for the pool
 func WithPoolLogger(logger *log.Logger) func(*ConnectionPool) {
 	return func(p *ConnectionPool) {
@@ -77,9 +177,60 @@ func WithPoolPingTimeout(timeout time.Duration) func(*ConnectionPool) {
 	}
 }
 
+// WithPoolHealthCheck Option to periodically ping idle connections and replace dead ones
+func WithPoolHealthCheck(interval time.Duration) func(*ConnectionPool) {
+	return func(p *ConnectionPool) {
+		p.healthInterval = interval
+	}
+}
+
+// WithPoolMaxIdleTime Option to close idle connections with no activity for longer than d,
+// before NATs or load balancers silently drop them
+func WithPoolMaxIdleTime(d time.Duration) func(*ConnectionPool) {
+	return func(p *ConnectionPool) {
+		p.maxIdleTime = d
+	}
+}
+
 // WithMiddleware sets the middleware function for the Server.
 func WithMiddleware(mw func(net.Conn) bool) ServerOption {
 	return func(s *Server) {
 		s.middleware = mw
 	}
 }
+
+// WithOverflowPolicy sets how the Server handles connections accepted while maxConns is reached.
+// With OverflowQueue, up to queueSize connections wait for a free slot; the rest are rejected.
+// A queueSize <= 0 keeps the default of 64.
+func WithOverflowPolicy(policy OverflowPolicy, queueSize int) ServerOption {
+	return func(s *Server) {
+		s.overflowPolicy = policy
+		if queueSize > 0 {
+			s.overflowQueueSize = queueSize
+		}
+	}
+}
+
+// WithOverflowQueueWait sets how long a connection may wait in the overflow queue for
+// a free slot before it is closed. Defaults to 5s.
+func WithOverflowQueueWait(d time.Duration) ServerOption {
+	return func(s *Server) {
+		if d > 0 {
+			s.overflowQueueWait = d
+		}
+	}
+}
+
+// WithOnClose registers a function called after each handled connection is closed.
+func WithOnClose(fn func(net.Conn)) ServerOption {
+	return func(s *Server) {
+		s.onClose = append(s.onClose, fn)
+	}
+}
+
+// WithRateLimiter sets RateLimitMiddleware as the Server middleware.
+func WithRateLimiter(limiter *RateLimiter) ServerOption {
+	return func(s *Server) {
+		s.middleware = RateLimitMiddleware(limiter)
+	}
+}
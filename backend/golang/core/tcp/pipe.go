@@ -0,0 +1,67 @@
+package tcp
+
+import (
+	"context"
+	"io"
+	"net"
+)
+
+// PipeStats holds the number of bytes copied in each direction by PipeWithStats.
+type PipeStats struct {
+	AToB int64
+	BToA int64
+}
+
+// Pipe copies data between a and b in both directions until both sides are done,
+// one of them fails or ctx is cancelled. See PipeWithStats.
+func Pipe(ctx context.Context, a, b net.Conn) error {
+	_, err := PipeWithStats(ctx, a, b)
+	return err
+}
+
+// PipeWithStats is like Pipe but also reports the bytes copied in each direction.
+// When one side finishes sending (EOF), the other side's write half is closed where
+// the connection supports it, so the response can still flow back, as a TCP proxy should.
+// Pipe owns both connections and closes them before returning. Returns the first copy
+// error, ctx.Err() if ctx was cancelled, or nil once both directions reached EOF.
+func PipeWithStats(ctx context.Context, a, b net.Conn) (PipeStats, error) {
+	var stats PipeStats
+	errc := make(chan error, 2)
+
+	closeBoth := func() {
+		a.Close()
+		b.Close()
+	}
+	defer closeBoth()
+	stop := context.AfterFunc(ctx, closeBoth)
+	defer stop()
+
+	forward := func(dst, src net.Conn, n *int64) {
+		written, err := io.Copy(dst, src)
+		*n = written
+		if err == nil {
+			if cw, ok := dst.(interface{ CloseWrite() error }); ok {
+				cw.CloseWrite()
+			}
+		}
+		errc <- err
+	}
+	go forward(b, a, &stats.AToB)
+	go forward(a, b, &stats.BToA)
+
+	var first error
+	for range 2 {
+		if err := <-errc; err != nil && first == nil {
+			first = err
+			// Unblock the other direction
+			closeBoth()
+		}
+	}
+	if ctx.Err() != nil {
+		return stats, ctx.Err()
+	}
+	if first != nil {
+		return stats, wrapError("pipe", first, false)
+	}
+	return stats, nil
+}
@@ -1,13 +1,19 @@
 package tcp
 
 import (
+	"context"
 	"errors"
 	"io"
 	"log"
 	"net"
+	"sync"
+	"sync/atomic"
 	"time"
 )
 
+// ErrPoolClosed is returned by Get and GetContext after the pool is closed.
+var ErrPoolClosed = errors.New("connection pool closed")
+
 // ConnectionPool manages a pool of TCP client connections.
 type ConnectionPool struct {
 	factory func() (*Client, error)
@@ -16,63 +22,242 @@ type ConnectionPool struct {
 	logger  *log.Logger
 	// Add a timeout for the ping check
 	pingTimeout time.Duration
+	// Interval of the background idle-connection health check (0 = disabled)
+	healthInterval time.Duration
+	// Idle connections without activity for longer are closed by the reaper (0 = disabled)
+	maxIdleTime time.Duration
+
+	// Connections handed out by Get/GetContext and not yet Put back
+	outstanding atomic.Int64
+	// Signalled by Put to wake GetContext callers waiting for a free slot
+	released chan struct{}
+
+	// Guards sends on and closing of the pool channel
+	mu        sync.Mutex
+	closed    bool
+	closeOnce sync.Once
+
+	// For background health checks
+	stopCh chan struct{}
+	wg     sync.WaitGroup
 }
 
 // NewConnectionPool creates a new connection pool.
-func NewConnectionPool(factory func() (*Client, error), maxSize int) *ConnectionPool {
-	// Consider adding options, e.g., for pingTimeout
-	pingTimeout := 1 * time.Second // Default ping timeout
-	return &ConnectionPool{
+func NewConnectionPool(factory func() (*Client, error), maxSize int, opts ...func(*ConnectionPool)) *ConnectionPool {
+	// The probe read tries the socket before waiting, so a dead peer is seen at once
+	// and an alive connection only costs this wait
+	pingTimeout := 5 * time.Millisecond // Default ping timeout
+	p := &ConnectionPool{
 		factory:     factory,
 		pool:        make(chan *Client, maxSize),
 		maxSize:     maxSize,
 		logger:      log.New(io.Discard, "[Pool] ", 0),
 		pingTimeout: pingTimeout,
+		released:    make(chan struct{}, maxSize),
+		stopCh:      make(chan struct{}),
+	}
+
+	for _, opt := range opts {
+		opt(p)
+	}
+
+	if p.healthInterval > 0 {
+		p.wg.Add(1)
+		go p.healthLoop()
+	}
+	if p.maxIdleTime > 0 {
+		p.wg.Add(1)
+		go p.reapLoop()
+	}
+
+	return p
+}
+
+// healthLoop periodically pings idle connections until the pool is closed.
+func (p *ConnectionPool) healthLoop() {
+	defer p.wg.Done()
+	ticker := time.NewTicker(p.healthInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			p.checkIdle()
+		case <-p.stopCh:
+			return
+		}
+	}
+}
+
+// checkIdle pings every idle connection once and replaces the dead ones,
+// so Get rarely hands out a stale connection.
+func (p *ConnectionPool) checkIdle() {
+	idle := len(p.pool)
+	for i := 0; i < idle; i++ {
+		var conn *Client
+		select {
+		case conn = <-p.pool:
+		default:
+			return // Drained concurrently by Get
+		}
+
+		if !p.ping(conn) {
+			p.logger.Printf("Health check: idle connection failed ping, replacing.")
+			_ = conn.Close()
+			newConn, err := p.factory()
+			if err != nil {
+				p.logger.Printf("Health check: failed to create replacement connection: %v", err)
+				continue
+			}
+			conn = newConn
+		}
+		p.putIdle(conn)
+	}
+}
+
+// reapLoop periodically closes connections idle for too long until the pool is closed.
+func (p *ConnectionPool) reapLoop() {
+	defer p.wg.Done()
+	// Check twice per period so a connection outlives maxIdleTime by at most half of it
+	ticker := time.NewTicker(p.maxIdleTime / 2)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			p.reapIdle()
+		case <-p.stopCh:
+			return
+		}
 	}
 }
 
-// Ping checks if a connection is likely alive.
-// This is a basic check; a real ping might involve writing/reading data.
+// reapIdle closes every idle connection whose last read or write is older than maxIdleTime
+// and puts the others back.
+func (p *ConnectionPool) reapIdle() {
+	idle := len(p.pool)
+	for i := 0; i < idle; i++ {
+		var conn *Client
+		select {
+		case conn = <-p.pool:
+		default:
+			return // Drained concurrently by Get
+		}
+
+		if since := time.Since(conn.Stats().LastActivity); since > p.maxIdleTime {
+			p.logger.Printf("Reaper: closing connection %s idle for %v", conn.RemoteAddr(), since)
+			if err := conn.Close(); err != nil {
+				p.logger.Printf("Error closing connection %s: %v", conn.RemoteAddr(), err)
+			}
+			continue
+		}
+		p.putIdle(conn)
+	}
+}
+
+// Ping checks if a connection is likely alive by reading one byte under a short deadline.
+// A timeout means the peer is connected but silent, as an idle connection should be.
+// EOF or another error means it is dead. A byte actually read is unsolicited data the
+// next request would mistake for its response, so such a connection is rejected too.
+// Zero-byte reads can't be used as a probe: Go returns without touching the socket.
 func (p *ConnectionPool) ping(conn *Client) bool {
 	if conn == nil || conn.conn == nil { // Use conn.conn to access the underlying net.Conn
 		return false
 	}
-	// Simple check: Set a short deadline and try a zero-byte read.
-	// This often reveals closed connections without sending data.
-	// Note: This isn't foolproof and might not work reliably across all OS/network conditions.
 	err := conn.conn.SetReadDeadline(time.Now().Add(p.pingTimeout))
 	if err != nil {
 		p.logger.Printf("Ping: failed to set deadline for %s: %v", conn.RemoteAddr(), err)
 		return false // Assume dead if can't set deadline
 	}
 
-	_, err = conn.conn.Read(make([]byte, 0)) // Attempt zero-byte read
-	conn.conn.SetReadDeadline(time.Time{})   // Clear deadline immediately
+	n, err := conn.conn.Read(make([]byte, 1))
+	conn.conn.SetReadDeadline(time.Time{}) // Clear deadline immediately
 
 	var netErr net.Error
 	if errors.As(err, &netErr) && netErr.Timeout() {
-		// Timeout on zero-byte read usually means connection is alive (waiting for data)
+		// Nothing to read within the deadline: alive and idle
 		return true
 	}
+	if n > 0 {
+		p.logger.Printf("Ping: connection %s has unexpected pending data", conn.RemoteAddr())
+		return false
+	}
 	if err == io.EOF || errors.Is(err, net.ErrClosed) {
 		// EOF or explicitly closed means connection is dead
 		p.logger.Printf("Ping: connection %s seems dead: %v", conn.RemoteAddr(), err)
 		return false
 	}
 	// Other errors might indicate issues, treat as dead for safety
+	p.logger.Printf("Ping: connection %s returned error: %v", conn.RemoteAddr(), err)
+	return false
+}
+
+// Get retrieves a connection from the pool. If the pool is empty, it creates a new one,
+// even when maxSize connections are already in use; see GetContext for a bounded Get.
+func (p *ConnectionPool) Get() (*Client, error) {
+	p.outstanding.Add(1)
+	conn, err := p.take()
 	if err != nil {
-		p.logger.Printf("Ping: connection %s returned error: %v", conn.RemoteAddr(), err)
-		return false
+		p.release()
+		return nil, err
 	}
+	return conn, nil
+}
 
-	// No error on zero-byte read (might happen on some systems) - assume alive
-	return true
+// GetContext retrieves a connection like Get, but never has more than maxSize
+// connections in use at once: when all of them are, it blocks until one is Put back
+// or ctx is done. Together with the idle ones, at most maxSize connections are alive.
+func (p *ConnectionPool) GetContext(ctx context.Context) (*Client, error) {
+	for !p.reserve() {
+		select {
+		case <-p.released:
+		case <-ctx.Done():
+			return nil, wrapError("pool get", ctx.Err(), false)
+		}
+	}
+	if err := ctx.Err(); err != nil {
+		p.release()
+		return nil, wrapError("pool get", err, false)
+	}
+
+	conn, err := p.take()
+	if err != nil {
+		p.release()
+		return nil, err
+	}
+	return conn, nil
 }
 
-// Get retrieves a connection from the pool. If the pool is empty, it creates a new one.
-func (p *ConnectionPool) Get() (*Client, error) {
+// reserve claims one of the maxSize in-use slots, reports false if none is free.
+func (p *ConnectionPool) reserve() bool {
+	for {
+		n := p.outstanding.Load()
+		if n >= int64(p.maxSize) {
+			return false
+		}
+		if p.outstanding.CompareAndSwap(n, n+1) {
+			return true
+		}
+	}
+}
+
+// release frees an in-use slot and wakes a waiting GetContext.
+func (p *ConnectionPool) release() {
+	p.outstanding.Add(-1)
 	select {
-	case conn := <-p.pool:
+	case p.released <- struct{}{}:
+	default:
+		// maxSize wakeups are already pending, enough for every free slot
+	}
+}
+
+// take returns an idle connection, or a new one if the pool is empty.
+func (p *ConnectionPool) take() (*Client, error) {
+	select {
+	case conn, ok := <-p.pool:
+		if !ok {
+			return nil, wrapError("pool get", ErrPoolClosed, false)
+		}
 		// Check if the connection is still alive before returning
 		if !p.ping(conn) {
 			p.logger.Printf("Connection from pool failed ping, closing and creating new.")
@@ -89,11 +274,30 @@ func (p *ConnectionPool) Get() (*Client, error) {
 	}
 }
 
-// Put returns a connection to the pool if there is space. Otherwise, it closes the connection.
+// Put returns a connection obtained from Get or GetContext to the pool if there is space.
+// Otherwise, it closes the connection. Either way its in-use slot is freed.
 func (p *ConnectionPool) Put(conn *Client) {
 	if conn == nil {
 		return
 	}
+	// Park the connection before freeing the slot, so a woken GetContext finds it
+	p.putIdle(conn)
+	p.release()
+}
+
+// discard closes a connection obtained from Get or GetContext instead of returning it,
+// freeing its in-use slot.
+func (p *ConnectionPool) discard(conn *Client) {
+	if err := conn.Close(); err != nil {
+		p.logger.Printf("Error closing connection %s: %v", conn.RemoteAddr(), err)
+	}
+	p.release()
+}
+
+// putIdle parks conn in the pool, or closes it if the pool is full or closed.
+func (p *ConnectionPool) putIdle(conn *Client) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
 
 	// Optional: Check connection health before putting back?
 	// if !p.ping(conn) {
@@ -102,6 +306,14 @@ func (p *ConnectionPool) Put(conn *Client) {
 	//    return
 	// }
 
+	if p.closed {
+		p.logger.Printf("Pool closed, closing connection %s", conn.RemoteAddr())
+		if err := conn.Close(); err != nil {
+			p.logger.Printf("Error closing connection %s: %v", conn.RemoteAddr(), err)
+		}
+		return
+	}
+
 	select {
 	case p.pool <- conn:
 		p.logger.Printf("Connection %s returned to pool", conn.RemoteAddr())
@@ -115,9 +327,21 @@ func (p *ConnectionPool) Put(conn *Client) {
 }
 
 // Close closes all connections in the pool and empties it.
+// Connections Put back afterwards are closed. Calling Close more than once is a no-op.
 func (p *ConnectionPool) Close() {
+	p.closeOnce.Do(p.close)
+}
+
+func (p *ConnectionPool) close() {
 	p.logger.Printf("Closing connection pool...")
+	// Stop background checks first, they put connections back into the channel
+	close(p.stopCh)
+	p.wg.Wait()
+
+	p.mu.Lock()
+	p.closed = true
 	close(p.pool) // Close the channel to prevent new puts
+	p.mu.Unlock()
 
 	// Drain the channel and close connections
 	for conn := range p.pool {
@@ -127,3 +351,41 @@ func (p *ConnectionPool) Close() {
 	}
 	p.logger.Printf("Connection pool closed.")
 }
+
+// DialPooled takes a connection from the pool and returns it with a release func
+// that puts it back. The release func is safe to call more than once.
+// If ctx is done before release, the connection is closed rather than put back, so a
+// cancelled request can't leak it, and a caller still using it can't share it with the
+// next Get: its pending reads and writes fail instead.
+// The client must not be used after release.
+func DialPooled(ctx context.Context, pool *ConnectionPool) (*Client, func(), error) {
+	if err := ctx.Err(); err != nil {
+		return nil, nil, wrapError("dial pooled", err, false)
+	}
+
+	client, err := pool.Get()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	done := make(chan struct{})
+	var once sync.Once
+	release := func() {
+		once.Do(func() {
+			close(done)
+			pool.Put(client)
+		})
+	}
+
+	go func() {
+		select {
+		case <-ctx.Done():
+			once.Do(func() {
+				pool.discard(client)
+			})
+		case <-done:
+		}
+	}()
+
+	return client, release, nil
+}
@@ -0,0 +1,292 @@
+package tcp
+
+import (
+	"context"
+	"errors"
+	"net"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// serveHold accepts connections on a local listener and keeps them open until the test ends.
+func serveHold(t *testing.T) string {
+	t.Helper()
+	ln, err := net.Listen(TCP, "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	done := make(chan struct{})
+	t.Cleanup(func() {
+		ln.Close()
+		<-done
+	})
+	go func() {
+		defer close(done)
+		var conns []net.Conn
+		defer func() {
+			for _, conn := range conns {
+				conn.Close()
+			}
+		}()
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			conns = append(conns, conn)
+		}
+	}()
+	return ln.Addr().String()
+}
+
+// countingFactory connects clients to address and tracks how many are alive.
+func countingFactory(t *testing.T, address string, live *atomic.Int64) func() (*Client, error) {
+	return func() (*Client, error) {
+		client, err := NewClient(address, nil)
+		if err != nil {
+			return nil, err
+		}
+		if err := client.Connect(); err != nil {
+			return nil, err
+		}
+		live.Add(1)
+		t.Cleanup(func() { client.Close() })
+		return client, nil
+	}
+}
+
+func TestPoolGetContextBoundsLiveConnections(t *testing.T) {
+	const maxSize = 3
+	var live atomic.Int64
+	pool := NewConnectionPool(countingFactory(t, serveHold(t), &live), maxSize)
+	defer pool.Close()
+
+	var (
+		wg    sync.WaitGroup
+		inUse atomic.Int64
+		peak  atomic.Int64
+	)
+	for range 20 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for range 5 {
+				conn, err := pool.GetContext(context.Background())
+				if err != nil {
+					t.Error(err)
+					return
+				}
+				n := inUse.Add(1)
+				for p := peak.Load(); n > p && !peak.CompareAndSwap(p, n); p = peak.Load() {
+				}
+				time.Sleep(time.Millisecond)
+				inUse.Add(-1)
+				pool.Put(conn)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if got := peak.Load(); got > maxSize {
+		t.Fatalf("%d connections in use at once, want at most %d", got, maxSize)
+	}
+	if got := live.Load(); got > maxSize {
+		t.Fatalf("%d connections created, want at most %d", got, maxSize)
+	}
+}
+
+func TestPoolGetContextWaitsForPut(t *testing.T) {
+	var live atomic.Int64
+	pool := NewConnectionPool(countingFactory(t, serveHold(t), &live), 1)
+	defer pool.Close()
+
+	conn, err := pool.GetContext(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if _, err := pool.GetContext(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("got %v, want deadline exceeded while the only connection is in use", err)
+	}
+
+	got := make(chan *Client)
+	go func() {
+		c, err := pool.GetContext(context.Background())
+		if err != nil {
+			t.Error(err)
+		}
+		got <- c
+	}()
+	time.Sleep(10 * time.Millisecond)
+	pool.Put(conn)
+
+	select {
+	case c := <-got:
+		if c != conn {
+			t.Fatal("expected the returned connection to be reused")
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("GetContext did not unblock after Put")
+	}
+}
+
+func TestPoolReapsIdleConnections(t *testing.T) {
+	var live atomic.Int64
+	pool := NewConnectionPool(countingFactory(t, serveHold(t), &live), 2, WithPoolMaxIdleTime(30*time.Millisecond))
+	defer pool.Close()
+
+	a, err := pool.Get()
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, err := pool.Get()
+	if err != nil {
+		t.Fatal(err)
+	}
+	pool.Put(a)
+	pool.Put(b)
+
+	deadline := time.Now().Add(2 * time.Second)
+	for len(pool.pool) > 0 {
+		if time.Now().After(deadline) {
+			t.Fatalf("%d stale connections still pooled", len(pool.pool))
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	for _, conn := range []*Client{a, b} {
+		if addr := conn.RemoteAddr(); addr != nil {
+			t.Fatalf("stale connection to %s was not closed", addr)
+		}
+	}
+
+	// The pool keeps working with fresh connections
+	c, err := pool.Get()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if c == a || c == b {
+		t.Fatal("got a reaped connection from the pool")
+	}
+	pool.Put(c)
+}
+
+func TestPoolHealthCheckReplacesClosedConnection(t *testing.T) {
+	ln, err := net.Listen(TCP, "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ln.Close()
+	accepted := make(chan net.Conn, 4)
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			accepted <- conn
+		}
+	}()
+
+	var live atomic.Int64
+	pool := NewConnectionPool(countingFactory(t, ln.Addr().String(), &live), 1)
+	defer pool.Close()
+
+	stale, err := pool.Get()
+	if err != nil {
+		t.Fatal(err)
+	}
+	pool.Put(stale)
+	if !pool.ping(stale) {
+		t.Fatal("ping rejected a healthy idle connection")
+	}
+
+	// The peer drops the idle connection
+	(<-accepted).Close()
+	time.Sleep(20 * time.Millisecond)
+	pool.checkIdle()
+	defer func() {
+		for len(accepted) > 0 {
+			(<-accepted).Close()
+		}
+	}()
+
+	if got := live.Load(); got != 2 {
+		t.Fatalf("%d connections created, want a replacement for the closed one", got)
+	}
+	if addr := stale.RemoteAddr(); addr != nil {
+		t.Fatal("closed connection was not closed on the client side")
+	}
+	fresh, err := pool.Get()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if fresh == stale {
+		t.Fatal("got the closed connection from the pool")
+	}
+	pool.Put(fresh)
+}
+
+func TestDialPooledCancelAfterPoolClose(t *testing.T) {
+	var live atomic.Int64
+	pool := NewConnectionPool(countingFactory(t, serveHold(t), &live), 1)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	client, release, err := DialPooled(ctx, pool)
+	if err != nil {
+		t.Fatal(err)
+	}
+	other, err := pool.Get()
+	if err != nil {
+		t.Fatal(err)
+	}
+	pool.Close()
+	cancel()
+
+	// The auto-release must close the client instead of sending on the closed pool
+	deadline := time.Now().Add(2 * time.Second)
+	for client.RemoteAddr() != nil {
+		if time.Now().After(deadline) {
+			t.Fatal("client not closed after ctx was cancelled")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	release()
+
+	// Put after Close closes the connection instead of panicking
+	pool.Put(other)
+	if other.RemoteAddr() != nil {
+		t.Fatal("connection Put after Close was not closed")
+	}
+	pool.Close()
+
+	if _, err := pool.Get(); !errors.Is(err, ErrPoolClosed) {
+		t.Fatalf("got %v, want ErrPoolClosed", err)
+	}
+}
+
+func TestDialPooledCancelDoesNotReturnClient(t *testing.T) {
+	var live atomic.Int64
+	pool := NewConnectionPool(countingFactory(t, serveHold(t), &live), 1)
+	defer pool.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	client, release, err := DialPooled(ctx, pool)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer release()
+	cancel()
+
+	next, err := pool.GetContext(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer pool.Put(next)
+	if next == client {
+		t.Fatal("a cancelled DialPooled client was handed to the next caller")
+	}
+}
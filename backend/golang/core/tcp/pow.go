@@ -1,6 +1,7 @@
 package tcp
 
 import (
+	"context"
 	cryptorand "crypto/rand"
 	"crypto/sha256"
 	"encoding/binary"
@@ -56,9 +57,7 @@ func ValidatePoWSolution(challenge *PoWChallenge, solution *PoWSolution) bool {
 		return false
 	}
 
-	buf := make([]byte, 8+32+8)
-	binary.BigEndian.PutUint64(buf[0:8], uint64(challenge.Timestamp))
-	copy(buf[8:40], challenge.RandomBytes)
+	buf := powInput(challenge)
 	binary.BigEndian.PutUint64(buf[40:48], solution.Nonce)
 
 	hash := sha256.Sum256(buf)
@@ -67,6 +66,57 @@ func ValidatePoWSolution(challenge *PoWChallenge, solution *PoWSolution) bool {
 	return leadingZeros >= challenge.Difficulty
 }
 
+// powInput returns the hashed layout of a challenge: timestamp, random bytes and
+// an 8-byte nonce slot at [40:48].
+func powInput(challenge *PoWChallenge) []byte {
+	buf := make([]byte, 8+32+8)
+	binary.BigEndian.PutUint64(buf[0:8], uint64(challenge.Timestamp))
+	copy(buf[8:40], challenge.RandomBytes)
+	return buf
+}
+
+// powCancelCheckInterval is how many nonces SolvePoWChallengeContext tries between ctx checks.
+const powCancelCheckInterval = 1 << 14
+
+// SolvePoWChallenge finds the first nonce accepted by ValidatePoWSolution for the challenge.
+// Each extra difficulty bit doubles the expected work; see SolvePoWChallengeContext to bound it.
+func SolvePoWChallenge(challenge *PoWChallenge) (*PoWSolution, error) {
+	return SolvePoWChallengeContext(context.Background(), challenge)
+}
+
+// SolvePoWChallengeContext is like SolvePoWChallenge but gives up with ctx.Err()
+// once ctx is done. The solution must still reach the server before the challenge
+// expires, 60 seconds after its timestamp.
+func SolvePoWChallengeContext(ctx context.Context, challenge *PoWChallenge) (*PoWSolution, error) {
+	if challenge == nil {
+		return nil, fmt.Errorf("challenge is nil")
+	}
+	if challenge.Difficulty < 0 || challenge.Difficulty > 256 {
+		return nil, fmt.Errorf("invalid difficulty")
+	}
+
+	buf := powInput(challenge)
+	nonce := uint64(0)
+	for {
+		if nonce%powCancelCheckInterval == 0 {
+			if err := ctx.Err(); err != nil {
+				return nil, err
+			}
+		}
+
+		binary.BigEndian.PutUint64(buf[40:48], nonce)
+		hash := sha256.Sum256(buf)
+		if countLeadingZeros(hash[:]) >= challenge.Difficulty {
+			return &PoWSolution{Nonce: nonce}, nil
+		}
+
+		nonce++
+		if nonce == 0 {
+			return nil, fmt.Errorf("no nonce satisfies difficulty %d", challenge.Difficulty)
+		}
+	}
+}
+
 // countLeadingZeros counts the number of leading zeros in a byte slice.
 func countLeadingZeros(data []byte) int32 {
 	var zeros int32
@@ -0,0 +1,42 @@
+package tcp
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestSolvePoWChallenge(t *testing.T) {
+	for difficulty := int32(4); difficulty <= 8; difficulty++ {
+		challenge, err := GeneratePoWChallenge(difficulty)
+		if err != nil {
+			t.Fatal(err)
+		}
+		solution, err := SolvePoWChallenge(challenge)
+		if err != nil {
+			t.Fatalf("difficulty %d: %v", difficulty, err)
+		}
+		if !ValidatePoWSolution(challenge, solution) {
+			t.Fatalf("difficulty %d: nonce %d rejected by ValidatePoWSolution", difficulty, solution.Nonce)
+		}
+	}
+}
+
+func TestSolvePoWChallengeContextCancelled(t *testing.T) {
+	challenge, err := GeneratePoWChallenge(256)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	if _, err := SolvePoWChallengeContext(ctx, challenge); !errors.Is(err, context.Canceled) {
+		t.Fatalf("got %v, want context.Canceled", err)
+	}
+}
+
+func TestSolvePoWChallengeInvalidDifficulty(t *testing.T) {
+	if _, err := SolvePoWChallenge(&PoWChallenge{Difficulty: 257}); err == nil {
+		t.Fatal("expected error for difficulty above 256")
+	}
+}
@@ -0,0 +1,13 @@
+//go:build !(aix || darwin || dragonfly || freebsd || linux || netbsd || openbsd)
+
+package tcp
+
+import (
+	"errors"
+	"syscall"
+)
+
+// reusePortControl reports that SO_REUSEPORT is unavailable on this platform.
+func reusePortControl(_, _ string, _ syscall.RawConn) error {
+	return errors.New("SO_REUSEPORT is not supported on this platform")
+}
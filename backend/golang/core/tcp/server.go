@@ -6,6 +6,8 @@ import (
 	"errors"
 	"log"
 	"net"
+	"slices"
+	"strings"
 	"sync"
 	"sync/atomic"
 	"time"
@@ -13,6 +15,22 @@ import (
 
 const (
 	defaultIdleTimeout = 5 * time.Minute
+
+	defaultAcceptBackoffMin = 5 * time.Millisecond
+	defaultAcceptBackoffMax = time.Second
+
+	defaultOverflowQueueSize = 64
+	defaultOverflowQueueWait = 5 * time.Second
+)
+
+// OverflowPolicy defines what the server does with connections accepted while maxConns is reached.
+type OverflowPolicy int
+
+const (
+	// OverflowReject closes new connections immediately (default).
+	OverflowReject OverflowPolicy = iota
+	// OverflowQueue parks new connections in a bounded queue and serves them as slots free up.
+	OverflowQueue
 )
 
 // ServerStats represents statistics about the server
@@ -32,6 +50,7 @@ type Server struct {
 	logger       *log.Logger
 	idleTimeout  time.Duration
 	tlsConfig    *tls.Config
+	tlsCurrent   atomic.Pointer[tls.Config] // Config used for new handshakes, swapped by ReloadTLS
 	ctx          context.Context
 	cancel       context.CancelFunc
 	stats        ServerStats
@@ -40,6 +59,28 @@ type Server struct {
 	maxConns     int64
 	currentConns int64
 	middleware   func(net.Conn) bool
+	onClose      []func(net.Conn)          // Called after a handled connection is closed
+	sniRoutes    map[string]func(net.Conn) // Handlers by lowercased TLS SNI hostname
+
+	reusePort         bool // Listen with SO_REUSEPORT so several processes can share the port
+	overflowPolicy    OverflowPolicy
+	overflowQueueSize int           // Max connections waiting for a free slot (OverflowQueue only)
+	overflowQueueWait time.Duration // Queued connections waiting longer are closed
+	queueMu           sync.Mutex
+	queue             []*queuedConn // Connections waiting for a free slot, oldest first
+
+	handshake *Features // Local capabilities exchanged on accept, nil disables the handshake
+
+	connsMu sync.Mutex
+	conns   map[net.Conn]*connEntry // Connections being handled, for Tag and CloseConnections
+
+	idempotent bool // Start/Stop return nil instead of an error when already in the target state
+
+	workers int // Fixed number of handler goroutines, 0 means one per connection
+
+	acceptBackoffMin time.Duration // First wait after a failed accept, doubled on each further failure
+	acceptBackoffMax time.Duration // Upper bound of the accept error backoff
+	jobs             chan net.Conn // Hands accepted connections to the workers
 }
 
 // NewServer creates a new TCP server with the given configuration
@@ -63,10 +104,16 @@ func NewServer(
 		handler:     handler,
 		tlsConfig:   tlsConfig,
 		idleTimeout: defaultIdleTimeout,
-		logger:      log.Default(),
-		ctx:         ctx,
-		cancel:      cancel,
-		maxConns:    65101, // default max connections
+
+		acceptBackoffMin:  defaultAcceptBackoffMin,
+		acceptBackoffMax:  defaultAcceptBackoffMax,
+		overflowQueueSize: defaultOverflowQueueSize,
+		overflowQueueWait: defaultOverflowQueueWait,
+
+		logger:   log.Default(),
+		ctx:      ctx,
+		cancel:   cancel,
+		maxConns: 65101, // default max connections
 		stats: ServerStats{
 			LastActivity: time.Now(),
 		},
@@ -85,66 +132,254 @@ func (s *Server) Start() error {
 	defer s.mu.Unlock()
 
 	if s.listener != nil {
+		if s.idempotent {
+			return nil
+		}
 		return errors.New("server already started")
 	}
 
-	listener, err := net.Listen(TCP, s.address)
+	if s.ctx.Err() != nil {
+		// Stopped before, start over with a fresh context
+		s.ctx, s.cancel = context.WithCancel(context.Background())
+	}
+
+	var lc net.ListenConfig
+	if s.reusePort {
+		lc.Control = reusePortControl
+	}
+
+	listener, err := lc.Listen(s.ctx, TCP, s.address)
 	if err != nil {
 		return wrapError("start server", err, false)
 	}
 
 	if s.tlsConfig != nil {
-		listener = tls.NewListener(listener, s.tlsConfig)
+		s.tlsCurrent.Store(s.tlsConfig)
+		listener = tls.NewListener(listener, &tls.Config{GetConfigForClient: s.configForClient})
 	}
 
 	s.listener = listener
 	s.stats.LastActivity = time.Now()
 
-	go s.acceptConnections()
+	if s.workers > 0 {
+		s.jobs = make(chan net.Conn)
+		s.wg.Add(s.workers)
+		for i := 0; i < s.workers; i++ {
+			go s.worker()
+		}
+	}
+
+	s.wg.Add(1)
+	go s.acceptConnections(listener)
 	s.logger.Printf("Server started on %s", s.address)
 	return nil
 }
 
 // acceptConnections accepts incoming connections and handles them
-func (s *Server) acceptConnections() {
+func (s *Server) acceptConnections(listener net.Listener) {
+	defer s.wg.Done()
+	var backoff time.Duration // Wait after the last accept error, 0 after a success
 	for {
 		select {
 		case <-s.ctx.Done():
 			// Server is stopping
-			if s.listener != nil {
-				if err := s.listener.Close(); err != nil && !errors.Is(err, net.ErrClosed) {
-					s.logger.Printf("Error closing listener: %v", err)
-				}
+			if err := listener.Close(); err != nil && !errors.Is(err, net.ErrClosed) {
+				s.logger.Printf("Error closing listener: %v", err)
 			}
 			return
 		default:
-			conn, err := s.listener.Accept()
+			conn, err := listener.Accept()
 			if err != nil {
 				if !errors.Is(err, net.ErrClosed) {
 					s.logger.Printf("Accept error: %v", err)
 				}
+				// Back off on repeated errors (e.g. EMFILE) instead of spinning
+				if backoff == 0 {
+					backoff = s.acceptBackoffMin
+				} else {
+					backoff = min(backoff*2, s.acceptBackoffMax)
+				}
 				// If the server context is cancelled, listener might be closed, so we return.
 				select {
 				case <-s.ctx.Done():
 					return
-				default:
+				case <-time.After(backoff):
 					// Continue accepting if it's a temporary error.
 					continue
 				}
 			}
+			backoff = 0
 
 			if atomic.LoadInt64(&s.currentConns) >= s.maxConns {
-				s.logger.Printf("Max connections reached, rejecting connection from %s", conn.RemoteAddr())
-				conn.Close()
+				s.handleOverflow(conn)
 				continue
 			}
 
 			atomic.AddInt64(&s.currentConns, 1)
-			atomic.AddInt64(&s.stats.TotalConnections, 1)
-			atomic.AddInt64(&s.stats.ActiveConnections, 1)
+			s.serve(conn)
+		}
+	}
+}
+
+// serve starts handling a connection whose slot was already reserved in currentConns.
+func (s *Server) serve(conn net.Conn) {
+	atomic.AddInt64(&s.stats.TotalConnections, 1)
+	atomic.AddInt64(&s.stats.ActiveConnections, 1)
+
+	s.wg.Add(1)
+	if s.jobs == nil {
+		go s.handleConnection(conn)
+		return
+	}
+
+	// Blocks while all workers are busy, which pushes back on the accept loop
+	select {
+	case s.jobs <- conn:
+	case <-s.ctx.Done():
+		atomic.AddInt64(&s.currentConns, -1)
+		atomic.AddInt64(&s.stats.ActiveConnections, -1)
+		conn.Close()
+		s.wg.Done()
+	}
+}
+
+// worker handles connections from the jobs channel until the server stops.
+func (s *Server) worker() {
+	defer s.wg.Done()
+	for {
+		select {
+		case conn := <-s.jobs:
+			s.handleConnection(conn)
+		case <-s.ctx.Done():
+			return
+		}
+	}
+}
+
+// releaseSlot hands a freed connection slot to a queued connection, if any.
+func (s *Server) releaseSlot() {
+	if s.overflowPolicy != OverflowQueue {
+		return
+	}
+	if s.jobs == nil {
+		s.serveQueued()
+		return
+	}
+	// Called from a worker, which must not block on handing work to itself
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+		s.serveQueued()
+	}()
+}
+
+// handleOverflow applies the overflow policy to a connection accepted while maxConns is reached.
+func (s *Server) handleOverflow(conn net.Conn) {
+	if s.overflowPolicy == OverflowQueue {
+		if s.enqueue(conn) {
+			s.logger.Printf("Max connections reached, queued connection from %s", conn.RemoteAddr())
+			// A slot may have been freed between the check and the enqueue.
+			s.serveQueued()
+			return
+		}
+		s.logger.Printf("Overflow queue full, rejecting connection from %s", conn.RemoteAddr())
+		conn.Close()
+		return
+	}
+
+	s.logger.Printf("Max connections reached, rejecting connection from %s", conn.RemoteAddr())
+	conn.Close()
+}
+
+// queuedConn is a connection waiting in the overflow queue.
+type queuedConn struct {
+	conn  net.Conn
+	timer *time.Timer // Closes the connection once it waited overflowQueueWait
+}
+
+// enqueue parks conn in the overflow queue, reports false if the queue is full.
+func (s *Server) enqueue(conn net.Conn) bool {
+	s.queueMu.Lock()
+	defer s.queueMu.Unlock()
+	if len(s.queue) >= s.overflowQueueSize {
+		return false
+	}
+	q := &queuedConn{conn: conn}
+	q.timer = time.AfterFunc(s.overflowQueueWait, func() { s.expireQueued(q) })
+	s.queue = append(s.queue, q)
+	return true
+}
+
+// dequeue removes and returns the oldest queued connection, or nil if none is waiting.
+func (s *Server) dequeue() net.Conn {
+	s.queueMu.Lock()
+	defer s.queueMu.Unlock()
+	if len(s.queue) == 0 {
+		return nil
+	}
+	q := s.queue[0]
+	s.queue[0] = nil
+	s.queue = s.queue[1:]
+	q.timer.Stop()
+	return q.conn
+}
+
+// expireQueued closes a connection that waited too long for a slot, unless it was
+// served in the meantime.
+func (s *Server) expireQueued(q *queuedConn) {
+	s.queueMu.Lock()
+	i := slices.Index(s.queue, q)
+	if i >= 0 {
+		s.queue = slices.Delete(s.queue, i, i+1)
+	}
+	s.queueMu.Unlock()
+	if i < 0 {
+		return
+	}
+
+	s.logger.Printf("Queued connection from %s waited %v for a slot, closing", q.conn.RemoteAddr(), s.overflowQueueWait)
+	if err := q.conn.Close(); err != nil && !errors.Is(err, net.ErrClosed) {
+		s.logger.Printf("Error closing queued connection from %s: %v", q.conn.RemoteAddr(), err)
+	}
+}
+
+// serveQueued moves queued connections into free slots until either runs out.
+func (s *Server) serveQueued() {
+	if s.overflowPolicy != OverflowQueue {
+		return
+	}
+	for {
+		current := atomic.LoadInt64(&s.currentConns)
+		if current >= s.maxConns {
+			return
+		}
+		// Reserve the slot first so concurrent callers can't overshoot maxConns.
+		if !atomic.CompareAndSwapInt64(&s.currentConns, current, current+1) {
+			continue
+		}
+
+		conn := s.dequeue()
+		if conn == nil {
+			// Nothing queued, release the reserved slot.
+			atomic.AddInt64(&s.currentConns, -1)
+			return
+		}
+		select {
+		case <-s.ctx.Done():
+			atomic.AddInt64(&s.currentConns, -1)
+			conn.Close()
+			return
+		default:
+		}
+		s.serve(conn)
+	}
+}
 
-			s.wg.Add(1)
-			go s.handleConnection(conn)
+// closeQueued closes all connections still waiting in the overflow queue.
+func (s *Server) closeQueued() {
+	for conn := s.dequeue(); conn != nil; conn = s.dequeue() {
+		if err := conn.Close(); err != nil && !errors.Is(err, net.ErrClosed) {
+			s.logger.Printf("Error closing queued connection from %s: %v", conn.RemoteAddr(), err)
 		}
 	}
 }
@@ -153,16 +388,24 @@ func (s *Server) acceptConnections() {
 func (s *Server) handleConnection(conn net.Conn) {
 	addr := conn.RemoteAddr()
 	s.logger.Printf("Connection from %s (%s)", addr, addr.Network())
+	untrack := s.track(conn)
 
 	defer func() {
+		untrack()
 		atomic.AddInt64(&s.currentConns, -1)
 		atomic.AddInt64(&s.stats.ActiveConnections, -1)
 		// Ensure connection is closed on exit, check error
 		if err := conn.Close(); err != nil && !errors.Is(err, net.ErrClosed) {
 			s.logger.Printf("Error closing connection from %s in defer: %v", addr, err)
 		}
-		s.wg.Done()
+		releaseConn(conn)
+		for _, fn := range s.onClose {
+			fn(conn)
+		}
 		s.logger.Printf("Connection closed: %s", addr) // Log connection closure
+		// Hand the freed slot to a queued connection, if any
+		s.releaseSlot()
+		s.wg.Done()
 	}()
 
 	if err := conn.SetDeadline(time.Now().Add(s.idleTimeout)); err != nil {
@@ -174,32 +417,96 @@ func (s *Server) handleConnection(conn net.Conn) {
 	ApplyMiddleware(conn, s.middleware, func(passedConn net.Conn) {
 		// If middleware passed, run the original handler
 		// Ensure the handler also manages deadlines if necessary
-		s.handler(passedConn)
+		handler, err := s.route(passedConn)
+		if err != nil {
+			s.logger.Printf("TLS handshake error from %s: %v", addr, err)
+			return
+		}
+		if s.handshake != nil {
+			features, err := handshake(passedConn, *s.handshake, s.idleTimeout, false)
+			if err != nil {
+				s.logger.Printf("Protocol handshake error from %s: %v", addr, err)
+				return
+			}
+			// Clearing the handshake deadline also cleared the idle deadline, restore it
+			if err := passedConn.SetDeadline(time.Now().Add(s.idleTimeout)); err != nil {
+				s.logger.Printf("Set deadline error: %v", err)
+				return
+			}
+			passedConn = &negotiatedConn{Conn: passedConn, features: features}
+		}
+		handler(passedConn)
 	})
 }
 
+// route picks the handler for a connection, completing the TLS handshake
+// first when SNI routing is configured so the requested hostname is known.
+func (s *Server) route(conn net.Conn) (func(net.Conn), error) {
+	if len(s.sniRoutes) == 0 {
+		return s.handler, nil
+	}
+	tlsConn, ok := conn.(*tls.Conn)
+	if !ok {
+		return s.handler, nil
+	}
+
+	// The handshake is bounded by the idle deadline already set on the connection
+	if err := tlsConn.HandshakeContext(s.ctx); err != nil {
+		return nil, err
+	}
+	if handler, ok := s.sniRoutes[strings.ToLower(tlsConn.ConnectionState().ServerName)]; ok {
+		return handler, nil
+	}
+	return s.handler, nil
+}
+
 // Stop gracefully stops the server
 func (s *Server) Stop() error {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
 	if s.listener == nil {
+		if s.idempotent {
+			return nil
+		}
 		return errors.New("server not started")
 	}
 
 	s.cancel() // Signal goroutines to stop
 
 	// Close the listener to stop accepting new connections
-	if err := s.listener.Close(); err != nil && !errors.Is(err, net.ErrClosed) {
+	listener := s.listener
+	s.listener = nil
+	if err := listener.Close(); err != nil && !errors.Is(err, net.ErrClosed) {
 		return wrapError("stop server", err, false)
 	}
 
+	// Drop connections that never got a slot
+	s.closeQueued()
+
 	// Wait for all active connections to close
 	s.wg.Wait()
 	s.logger.Printf("Server stopped")
 	return nil
 }
 
+// Restart stops the server if it is running and starts it again on the same address.
+func (s *Server) Restart() error {
+	if s.Running() {
+		if err := s.Stop(); err != nil {
+			return err
+		}
+	}
+	return s.Start()
+}
+
+// Running reports whether the server has been started and not stopped since.
+func (s *Server) Running() bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.listener != nil
+}
+
 // StopWithTimeout gracefully stops the server with a timeout
 func (s *Server) StopWithTimeout(timeout time.Duration) error {
 	deadline := time.After(timeout)
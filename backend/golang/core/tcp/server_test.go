@@ -201,3 +201,170 @@ func TestServerMiddlewareWithHighLoad(t *testing.T) {
 		// t.Errorf("Ожидались некоторые заблокированные подключения, но ни одного не было")
 	}
 }
+
+// startOverflowServer starts a server with a single connection slot and an overflow queue.
+// Every handled connection gets "hi" and is held open until release is closed.
+func startOverflowServer(t *testing.T, release <-chan struct{}, opts ...ServerOption) string {
+	t.Helper()
+	handler := func(conn net.Conn) {
+		conn.Write([]byte("hi"))
+		<-release
+	}
+	opts = append([]ServerOption{
+		WithServerLogger(log.New(io.Discard, "", 0)),
+		WithOverflowPolicy(OverflowQueue, 1),
+	}, opts...)
+	server, err := NewServer("127.0.0.1:0", handler, nil, opts...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	server.maxConns = 1
+	if err := server.Start(); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { server.Stop() })
+
+	server.mu.RLock()
+	defer server.mu.RUnlock()
+	return server.listener.Addr().String()
+}
+
+// readGreeting reads the handler's "hi", or returns the read error.
+func readGreeting(conn net.Conn, timeout time.Duration) error {
+	conn.SetReadDeadline(time.Now().Add(timeout))
+	buf := make([]byte, 2)
+	if _, err := io.ReadFull(conn, buf); err != nil {
+		return err
+	}
+	if string(buf) != "hi" {
+		return fmt.Errorf("got %q", buf)
+	}
+	return nil
+}
+
+func TestServerOverflowQueueHandsOffSlot(t *testing.T) {
+	release := make(chan struct{})
+	address := startOverflowServer(t, release)
+
+	first, err := net.Dial(TCP, address)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer first.Close()
+	if err := readGreeting(first, 2*time.Second); err != nil {
+		t.Fatal(err)
+	}
+
+	queued, err := net.Dial(TCP, address)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer queued.Close()
+	// Not served while the only slot is taken
+	if err := readGreeting(queued, 50*time.Millisecond); err == nil {
+		t.Fatal("queued connection was served before a slot freed")
+	}
+
+	// Freeing the slot hands it to the queued connection
+	close(release)
+	if err := readGreeting(queued, 2*time.Second); err != nil {
+		t.Fatalf("queued connection not served after the slot freed: %v", err)
+	}
+}
+
+func TestServerOverflowQueueWaitExpires(t *testing.T) {
+	release := make(chan struct{})
+	defer close(release)
+	address := startOverflowServer(t, release, WithOverflowQueueWait(50*time.Millisecond))
+
+	first, err := net.Dial(TCP, address)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer first.Close()
+	if err := readGreeting(first, 2*time.Second); err != nil {
+		t.Fatal(err)
+	}
+
+	queued, err := net.Dial(TCP, address)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer queued.Close()
+	// The server closes the connection once it waited too long, without serving it
+	if err := readGreeting(queued, 2*time.Second); err != io.EOF {
+		t.Fatalf("got %v, want EOF from the expired queued connection", err)
+	}
+}
+
+func TestWithOverflowPolicyDefaultQueueSize(t *testing.T) {
+	server, err := NewServer("127.0.0.1:0", func(net.Conn) {}, nil, WithOverflowPolicy(OverflowQueue, 0))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if server.overflowQueueSize != defaultOverflowQueueSize {
+		t.Fatalf("queue size %d, want default %d", server.overflowQueueSize, defaultOverflowQueueSize)
+	}
+}
+
+func TestServerReleasesPerIPSlotWithMiddleware(t *testing.T) {
+	limiter := NewRateLimiter(nil, WithMaxConcurrentPerIP(2))
+	defer limiter.Stop()
+
+	handler := func(conn net.Conn) {
+		conn.Write([]byte("hi"))
+		io.Copy(io.Discard, conn)
+	}
+	server, err := NewServer("127.0.0.1:0", handler, nil,
+		WithServerLogger(log.New(io.Discard, "", 0)),
+		WithMiddleware(RateLimitMiddleware(limiter)),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := server.Start(); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { server.Stop() }) // after the connections below are closed
+	server.mu.RLock()
+	address := server.listener.Addr().String()
+	server.mu.RUnlock()
+
+	dial := func() net.Conn {
+		t.Helper()
+		conn, err := net.Dial("tcp", address)
+		if err != nil {
+			t.Fatal(err)
+		}
+		t.Cleanup(func() { conn.Close() })
+		return conn
+	}
+
+	first := dial()
+	for _, conn := range []net.Conn{first, dial()} {
+		if err := readGreeting(conn, 2*time.Second); err != nil {
+			t.Fatalf("connection within the limit: %v", err)
+		}
+	}
+	if err := readGreeting(dial(), 2*time.Second); err == nil {
+		t.Fatal("third concurrent connection was accepted, want it rejected")
+	}
+
+	first.Close()
+	deadline := time.Now().Add(2 * time.Second)
+	for {
+		limiter.mu.RLock()
+		active := limiter.activePerIP["127.0.0.1"]
+		limiter.mu.RUnlock()
+		if active < 2 {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("closed connection still counted, %d active", active)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	if err := readGreeting(dial(), 2*time.Second); err != nil {
+		t.Fatalf("connection after a release: %v", err)
+	}
+}
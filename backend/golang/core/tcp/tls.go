@@ -2,6 +2,7 @@ package tcp
 
 import (
 	"crypto/tls"
+	"errors"
 )
 
 // ServerTLSConfig creates a TLS configuration for the server
@@ -24,3 +25,33 @@ func ClientTLSConfig(insecureSkipVerify bool) *tls.Config {
 		MinVersion:         tls.VersionTLS12,
 	}
 }
+
+// ReloadTLS replaces the TLS configuration of a running Server, e.g. after a certificate
+// rotation. New handshakes use config; established connections keep their session.
+// The Server must have been created with a TLS configuration.
+func (s *Server) ReloadTLS(config *tls.Config) error {
+	if config == nil {
+		return errors.New("tls config cannot be nil")
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.tlsConfig == nil {
+		return errors.New("server was not configured with TLS")
+	}
+	s.tlsConfig = config
+	s.tlsCurrent.Store(config)
+	s.logger.Printf("TLS configuration reloaded")
+	return nil
+}
+
+// configForClient serves every handshake from the current configuration.
+func (s *Server) configForClient(hello *tls.ClientHelloInfo) (*tls.Config, error) {
+	config := s.tlsCurrent.Load()
+	if config.GetConfigForClient != nil {
+		if c, err := config.GetConfigForClient(hello); c != nil || err != nil {
+			return c, err
+		}
+	}
+	return config, nil
+}
@@ -0,0 +1,182 @@
+package time
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// cronSearchYears bounds the search for a matching instant, so expressions
+// that can never fire (e.g. "0 0 30 2 *") fail instead of looping forever.
+const cronSearchYears = 5
+
+var (
+	cronMonthNames = map[string]int{
+		"jan": 1, "feb": 2, "mar": 3, "apr": 4, "may": 5, "jun": 6,
+		"jul": 7, "aug": 8, "sep": 9, "oct": 10, "nov": 11, "dec": 12,
+	}
+	cronDayNames = map[string]int{
+		"sun": 0, "mon": 1, "tue": 2, "wed": 3, "thu": 4, "fri": 5, "sat": 6,
+	}
+)
+
+// cronSchedule holds the allowed values of each field as bitsets.
+type cronSchedule struct {
+	minute, hour, dom, month, dow uint64
+	domAny, dowAny                bool // Field started with "*", affects how dom and dow combine
+}
+
+// NextCron returns the first instant strictly after the given time that matches a
+// standard 5-field cron expression (minute hour day-of-month month day-of-week).
+// Fields accept "*", values, ranges "a-b", lists "a,b" and steps "*/n" or "a-b/n";
+// months and weekdays also accept three-letter names, and 7 means Sunday.
+// As in cron, if both day fields are restricted a day matching either one fires;
+// a day field starting with "*", such as "*/2", doesn't count as restricted.
+// The result is computed in after's location. Times skipped by a DST spring-forward
+// never match, and the hour repeated by a fall-back matches in both offsets.
+func NextCron(expr string, after time.Time) (time.Time, error) {
+	s, err := parseCron(expr)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	loc := after.Location()
+	// Step from the absolute instant: rebuilding it with time.Date would resolve an
+	// hour repeated by DST to its first offset and could return after itself
+	t := after.Truncate(time.Minute).Add(time.Minute)
+	limit := after.Year() + cronSearchYears
+
+	// advance moves t to the start of the next unit; calendar arithmetic can land
+	// behind t around DST transitions, so fall back to an absolute step then.
+	advance := func(next time.Time, step time.Duration) time.Time {
+		if next.After(t) {
+			return next
+		}
+		return t.Add(step)
+	}
+
+	for t.Year() <= limit {
+		switch {
+		case s.month&(1<<uint(t.Month())) == 0:
+			t = advance(time.Date(t.Year(), t.Month()+1, 1, 0, 0, 0, 0, loc), time.Hour)
+		case !s.dayMatches(t):
+			t = advance(time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, loc), time.Hour)
+		case s.hour&(1<<uint(t.Hour())) == 0:
+			t = advance(time.Date(t.Year(), t.Month(), t.Day(), t.Hour()+1, 0, 0, 0, loc), time.Minute)
+		case s.minute&(1<<uint(t.Minute())) == 0:
+			t = t.Add(time.Minute)
+		default:
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("timeutil: cron expression %q has no match within %d years", expr, cronSearchYears)
+}
+
+// dayMatches applies cron's day rule: if either day field starts with "*", both must match,
+// otherwise matching either is enough.
+func (s *cronSchedule) dayMatches(t time.Time) bool {
+	domOK := s.dom&(1<<uint(t.Day())) != 0
+	dowOK := s.dow&(1<<uint(t.Weekday())) != 0
+	if s.domAny || s.dowAny {
+		return domOK && dowOK
+	}
+	return domOK || dowOK
+}
+
+// parseCron parses a 5-field cron expression.
+func parseCron(expr string) (*cronSchedule, error) {
+	fields := strings.Fields(expr)
+	if len(fields) != 5 {
+		return nil, fmt.Errorf("timeutil: cron expression %q must have 5 fields, got %d", expr, len(fields))
+	}
+
+	var (
+		s   cronSchedule
+		err error
+	)
+	if s.minute, err = parseCronField(fields[0], 0, 59, nil); err != nil {
+		return nil, err
+	}
+	if s.hour, err = parseCronField(fields[1], 0, 23, nil); err != nil {
+		return nil, err
+	}
+	if s.dom, err = parseCronField(fields[2], 1, 31, nil); err != nil {
+		return nil, err
+	}
+	if s.month, err = parseCronField(fields[3], 1, 12, cronMonthNames); err != nil {
+		return nil, err
+	}
+	if s.dow, err = parseCronField(fields[4], 0, 7, cronDayNames); err != nil {
+		return nil, err
+	}
+	// 7 is an alias for Sunday
+	if s.dow&(1<<7) != 0 {
+		s.dow = s.dow&^(1<<7) | 1
+	}
+	s.domAny = strings.HasPrefix(fields[2], "*")
+	s.dowAny = strings.HasPrefix(fields[4], "*")
+	return &s, nil
+}
+
+// parseCronField parses one field into a bitset of allowed values in [lo, hi].
+func parseCronField(field string, lo, hi int, names map[string]int) (uint64, error) {
+	var bits uint64
+	for _, part := range strings.Split(field, ",") {
+		rangePart, stepPart, hasStep := strings.Cut(part, "/")
+
+		step := 1
+		if hasStep {
+			n, err := strconv.Atoi(stepPart)
+			if err != nil || n <= 0 {
+				return 0, fmt.Errorf("timeutil: invalid cron step %q", part)
+			}
+			step = n
+		}
+
+		var start, end int
+		switch {
+		case rangePart == "*":
+			start, end = lo, hi
+		case strings.Contains(rangePart, "-"):
+			a, b, _ := strings.Cut(rangePart, "-")
+			var err error
+			if start, err = parseCronValue(a, lo, hi, names); err != nil {
+				return 0, err
+			}
+			if end, err = parseCronValue(b, lo, hi, names); err != nil {
+				return 0, err
+			}
+			if start > end {
+				return 0, fmt.Errorf("timeutil: invalid cron range %q", rangePart)
+			}
+		default:
+			var err error
+			if start, err = parseCronValue(rangePart, lo, hi, names); err != nil {
+				return 0, err
+			}
+			end = start
+			// "a/n" means every n starting at a
+			if hasStep {
+				end = hi
+			}
+		}
+
+		for v := start; v <= end; v += step {
+			bits |= 1 << uint(v)
+		}
+	}
+	return bits, nil
+}
+
+// parseCronValue parses a number or a name within [lo, hi].
+func parseCronValue(s string, lo, hi int, names map[string]int) (int, error) {
+	if v, ok := names[strings.ToLower(s)]; ok {
+		return v, nil
+	}
+	v, err := strconv.Atoi(s)
+	if err != nil || v < lo || v > hi {
+		return 0, fmt.Errorf("timeutil: invalid cron value %q, expected %d-%d", s, lo, hi)
+	}
+	return v, nil
+}
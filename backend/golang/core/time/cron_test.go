@@ -0,0 +1,139 @@
+package time
+
+import (
+	"testing"
+	"time"
+)
+
+func TestNextCron(t *testing.T) {
+	ny, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Skipf("no tz database: %v", err)
+	}
+	est := time.FixedZone("EST", -5*3600)
+	edt := time.FixedZone("EDT", -4*3600)
+
+	tests := []struct {
+		name  string
+		expr  string
+		after time.Time
+		want  time.Time
+	}{
+		{
+			name:  "strictly after a match",
+			expr:  "30 * * * *",
+			after: time.Date(2024, 1, 1, 10, 30, 0, 0, time.UTC),
+			want:  time.Date(2024, 1, 1, 11, 30, 0, 0, time.UTC),
+		},
+		{
+			name:  "seconds are dropped",
+			expr:  "* * * * *",
+			after: time.Date(2024, 1, 1, 10, 30, 59, 999, time.UTC),
+			want:  time.Date(2024, 1, 1, 10, 31, 0, 0, time.UTC),
+		},
+		{
+			name:  "fall-back first 01:30 fires again in the repeated hour",
+			expr:  "30 * * * *",
+			after: time.Date(2024, 11, 3, 1, 30, 0, 0, edt),
+			want:  time.Date(2024, 11, 3, 1, 30, 0, 0, est),
+		},
+		{
+			name:  "fall-back second 01:30 moves on",
+			expr:  "30 * * * *",
+			after: time.Date(2024, 11, 3, 1, 30, 0, 0, est),
+			want:  time.Date(2024, 11, 3, 2, 30, 0, 0, est),
+		},
+		{
+			name:  "spring-forward skips the missing hour",
+			expr:  "30 2 * * *",
+			after: time.Date(2024, 3, 10, 0, 0, 0, 0, est),
+			want:  time.Date(2024, 3, 11, 2, 30, 0, 0, edt),
+		},
+		{
+			name:  "spring-forward hourly",
+			expr:  "0 * * * *",
+			after: time.Date(2024, 3, 10, 1, 0, 0, 0, est),
+			want:  time.Date(2024, 3, 10, 3, 0, 0, 0, edt),
+		},
+		{
+			name:  "Feb 29 waits for a leap year",
+			expr:  "0 0 29 2 *",
+			after: time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC),
+			want:  time.Date(2028, 2, 29, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name:  "restricted dom and dow match either",
+			expr:  "0 0 1 * mon",
+			after: time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC), // Tuesday
+			want:  time.Date(2024, 1, 8, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name:  "restricted dom and dow, dom first",
+			expr:  "0 0 1 * fri",
+			after: time.Date(2024, 1, 27, 0, 0, 0, 0, time.UTC), // Saturday
+			want:  time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name:  "starred dom step requires both",
+			expr:  "0 0 */2 * mon",
+			after: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC), // Monday the 1st
+			want:  time.Date(2024, 1, 15, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name:  "month and weekday names",
+			expr:  "0 9 * mar-apr Mon-Fri",
+			after: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+			want:  time.Date(2024, 3, 1, 9, 0, 0, 0, time.UTC), // Friday
+		},
+		{
+			name:  "7 is Sunday",
+			expr:  "0 0 * * 7",
+			after: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+			want:  time.Date(2024, 1, 7, 0, 0, 0, 0, time.UTC),
+		},
+	}
+	for _, tt := range tests {
+		after := tt.after
+		if after.Location() != time.UTC {
+			after = after.In(ny)
+		}
+		got, err := NextCron(tt.expr, after)
+		if err != nil {
+			t.Errorf("%s: %v", tt.name, err)
+			continue
+		}
+		if !got.Equal(tt.want) {
+			t.Errorf("%s: got %v, want %v", tt.name, got, tt.want.In(ny))
+		}
+	}
+}
+
+func TestNextCronLoopAdvances(t *testing.T) {
+	ny, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Skipf("no tz database: %v", err)
+	}
+	// Feeding each result back in must always move forward, also across the DST overlap
+	next := time.Date(2024, 11, 3, 0, 0, 0, 0, ny)
+	for range 6 {
+		got, err := NextCron("30 * * * *", next)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !got.After(next) {
+			t.Fatalf("NextCron(%v) = %v, not after it", next, got)
+		}
+		next = got
+	}
+	if want := time.Date(2024, 11, 3, 4, 30, 0, 0, ny); !next.Equal(want) {
+		t.Fatalf("got %v, want %v", next, want)
+	}
+}
+
+func TestNextCronInvalid(t *testing.T) {
+	for _, expr := range []string{"* * * *", "60 * * * *", "* * * * 8", "*/0 * * * *", "5-1 * * * *", "0 0 30 2 *"} {
+		if _, err := NextCron(expr, time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)); err == nil {
+			t.Errorf("%q: expected error", expr)
+		}
+	}
+}
@@ -8,6 +8,13 @@ import (
 	"time"
 )
 
+// Now returns the current time and is used by every function in this package that depends on it.
+// Tests can replace it with a fixed clock and restore it afterwards:
+//
+//	Now = func() time.Time { return fixed }
+//	defer func() { Now = time.Now }()
+var Now = time.Now
+
 // CountDigitsInNumber returns the number of digits in an integer.
 // Handles zero and negative numbers by absolute value conversion.
 func CountDigitsInNumber(number int64) int {
@@ -45,21 +52,21 @@ func UnixToTime(unixTime int64) time.Time {
 // SecondsSince returns seconds between now and given timestamp (past-aware).
 func SecondsSince(unixTime int64) int {
 	t := UnixToTime(unixTime)
-	diff := time.Since(t)
+	diff := Now().Sub(t)
 	return int(math.Round(diff.Seconds()))
 }
 
 // SecondsUntil returns seconds between given timestamp and now (future-aware).
 func SecondsUntil(unixTime int64) int {
 	t := UnixToTime(unixTime)
-	diff := t.Sub(time.Now())
+	diff := t.Sub(Now())
 	return int(math.Round(diff.Seconds()))
 }
 
 // TimeTrack measures execution time and logs with customizable level.
 // Usage: defer TimeTrack(time.Now(), "operation", "DEBUG")
 func TimeTrack(start time.Time, name string, level ...string) {
-	elapsed := time.Since(start)
+	elapsed := Now().Sub(start)
 	logLevel := "INFO"
 	if len(level) > 0 {
 		logLevel = level[0]
@@ -87,7 +94,7 @@ func FormatDuration(d time.Duration) string {
 
 // UnixMilli returns current timestamp in milliseconds (Go 1.17+ compatible).
 func UnixMilli() int64 {
-	return time.Now().UnixNano() / int64(time.Millisecond)
+	return Now().UnixNano() / int64(time.Millisecond)
 }
 
 // ConvertToTimezone converts a time.Time to a specified timezone.
@@ -107,7 +114,7 @@ func NowInTimezone(timezone string) (time.Time, error) {
 	if err != nil {
 		return time.Time{}, fmt.Errorf("timeutil: invalid timezone %q", timezone)
 	}
-	return time.Now().In(loc), nil
+	return Now().In(loc), nil
 }
 
 // ParseInTimezone parses a time string in the specified timezone and layout.
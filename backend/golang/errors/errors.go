@@ -5,6 +5,7 @@ package errors
 import (
 	"errors"
 	"fmt"
+	"runtime/debug"
 
 	"github.com/hashicorp/go-multierror"
 )
@@ -83,3 +84,125 @@ func Combine(err error, msg string) error {
 	}
 	return Append(err, New(msg))
 }
+
+// Must returns v if err is nil and panics with err otherwise.
+// Intended for initialization code and tests where an error is unrecoverable;
+// never use it on request-handling paths.
+func Must[T any](v T, err error) T {
+	if err != nil {
+		panic(err)
+	}
+	return v
+}
+
+// Must0 panics if err is not nil.
+// Like Must, it is meant for initialization code and tests only.
+func Must0(err error) {
+	if err != nil {
+		panic(err)
+	}
+}
+
+// fieldsError attaches key/value context to an error.
+type fieldsError struct {
+	err    error
+	fields map[string]any
+}
+
+func (e *fieldsError) Error() string { return e.err.Error() }
+
+func (e *fieldsError) Unwrap() error { return e.err }
+
+// With attaches key/value pairs to err, like structured logging attributes.
+// Keys that are not strings are formatted with %v; a trailing key without a value
+// is stored under "!BADKEY". If err is nil, returns nil.
+func With(err error, keyvals ...any) error {
+	if err == nil {
+		return nil
+	}
+
+	fields := make(map[string]any, (len(keyvals)+1)/2)
+	for i := 0; i < len(keyvals); i += 2 {
+		if i+1 == len(keyvals) {
+			fields["!BADKEY"] = keyvals[i]
+			break
+		}
+		key, ok := keyvals[i].(string)
+		if !ok {
+			key = fmt.Sprint(keyvals[i])
+		}
+		fields[key] = keyvals[i+1]
+	}
+	return &fieldsError{err: err, fields: fields}
+}
+
+// Fields returns the key/value context attached with With anywhere in err's chain,
+// including errors combined with Join, Append or Combine. Outer values win over inner ones for the same key.
+// Returns nil if no fields are attached.
+func Fields(err error) map[string]any {
+	var result map[string]any
+	collectFields(err, func(fields map[string]any) {
+		if result == nil {
+			result = make(map[string]any, len(fields))
+		}
+		for k, v := range fields {
+			if _, ok := result[k]; !ok {
+				result[k] = v
+			}
+		}
+	})
+	return result
+}
+
+// collectFields walks the error tree from the outside in.
+func collectFields(err error, visit func(map[string]any)) {
+	for err != nil {
+		if fe, ok := err.(*fieldsError); ok {
+			visit(fe.fields)
+		}
+		switch u := err.(type) {
+		case *multierror.Error:
+			for _, inner := range u.Errors {
+				collectFields(inner, visit)
+			}
+			return
+		case interface{ Unwrap() []error }:
+			for _, inner := range u.Unwrap() {
+				collectFields(inner, visit)
+			}
+			return
+		case interface{ Unwrap() error }:
+			err = u.Unwrap()
+		default:
+			return
+		}
+	}
+}
+
+// PanicError is an error built from a recovered panic value.
+// If the value was itself an error, it stays reachable through Unwrap,
+// so errors.Is and errors.As see typed panics.
+type PanicError struct {
+	Value any    // Value passed to panic
+	Stack []byte // Stack trace captured where the panic was recovered
+}
+
+func (e *PanicError) Error() string { return fmt.Sprintf("panic: %v", e.Value) }
+
+// Unwrap returns the panic value if it is an error, nil otherwise.
+func (e *PanicError) Unwrap() error {
+	err, _ := e.Value.(error)
+	return err
+}
+
+// FromPanic converts a value returned by recover into a *PanicError carrying the
+// current stack trace; call it directly in the deferred function. Returns nil if r is nil.
+func FromPanic(r any) error {
+	if r == nil {
+		return nil
+	}
+	if pe, ok := r.(*PanicError); ok {
+		return pe
+	}
+	return &PanicError{Value: r, Stack: debug.Stack()}
+}
@@ -0,0 +1,35 @@
+package errors
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestFields(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want map[string]any
+	}{
+		{"nil", nil, nil},
+		{"none", New("a"), nil},
+		{"wrapped", Wrap(With(New("a"), "user_id", 7), "load"), map[string]any{"user_id": 7}},
+		{"outer wins", With(Wrap(With(New("a"), "id", 1), "b"), "id", 2), map[string]any{"id": 2}},
+		{"join", Join(New("a"), With(New("b"), "k", "v")), map[string]any{"k": "v"}},
+		{"append", Append(With(New("a"), "user_id", 7), New("b")), map[string]any{"user_id": 7}},
+		{"append later", Append(New("a"), With(New("b"), "k", "v")), map[string]any{"k": "v"}},
+		{"combine", Combine(With(New("a"), "user_id", 7), "b"), map[string]any{"user_id": 7}},
+		{
+			"wrapped append",
+			With(Wrap(Append(With(New("a"), "a", 1), With(New("b"), "b", 2)), "both"), "op", "sync"),
+			map[string]any{"a": 1, "b": 2, "op": "sync"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Fields(tt.err); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Fields() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
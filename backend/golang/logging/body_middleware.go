@@ -0,0 +1,299 @@
+package logging
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"mime"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+)
+
+const (
+	defaultBodyLogMaxSize = 4 << 10
+	redactedValue         = "[REDACTED]"
+)
+
+// defaultRedactedFields are JSON and form keys whose values are never logged, matched case-insensitively.
+var defaultRedactedFields = []string{
+	"password", "passwd", "secret", "token", "access_token", "refresh_token",
+	"authorization", "api_key", "apikey", "credit_card", "card_number", "cvv", "ssn",
+}
+
+// bodyLogConfig holds the BodyLoggingMiddleware settings.
+type bodyLogConfig struct {
+	maxSize int
+	level   Level
+	fields  map[string]struct{}
+}
+
+// BodyLogOption configures BodyLoggingMiddleware.
+type BodyLogOption func(*bodyLogConfig)
+
+// WithBodyMaxSize caps how many bytes of each body are logged. Defaults to 4 KiB.
+func WithBodyMaxSize(n int) BodyLogOption {
+	return func(c *bodyLogConfig) {
+		c.maxSize = n
+	}
+}
+
+// WithBodyLogLevel sets the level of the body log records. Defaults to debug.
+func WithBodyLogLevel(level Level) BodyLogOption {
+	return func(c *bodyLogConfig) {
+		c.level = level
+	}
+}
+
+// WithRedactedFields adds JSON and form keys whose values are replaced with "[REDACTED]",
+// on top of the default sensitive keys (password, token, authorization, ...).
+func WithRedactedFields(fields ...string) BodyLogOption {
+	return func(c *bodyLogConfig) {
+		for _, f := range fields {
+			c.fields[strings.ToLower(f)] = struct{}{}
+		}
+	}
+}
+
+// BodyLoggingMiddleware logs request and response bodies through the request logger,
+// for debugging specific endpoints; wrap only those, as bodies may hold personal data.
+// Bodies are captured up to a size cap while the handler reads and writes them, so the
+// handler sees the request body unchanged. Values of sensitive JSON and form keys are
+// redacted, also in bodies cut off by the cap; bodies of other types are logged as
+// "[REDACTED]".
+func BodyLoggingMiddleware(opts ...BodyLogOption) func(http.Handler) http.Handler {
+	config := &bodyLogConfig{
+		maxSize: defaultBodyLogMaxSize,
+		level:   LevelDebug,
+		fields:  make(map[string]struct{}, len(defaultRedactedFields)),
+	}
+	for _, f := range defaultRedactedFields {
+		config.fields[f] = struct{}{}
+	}
+	for _, opt := range opts {
+		opt(config)
+	}
+
+	return func(next http.Handler) http.Handler {
+		fn := func(w http.ResponseWriter, r *http.Request) {
+			logger := L(r.Context())
+			if !logger.Enabled(r.Context(), config.level) {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			reqBody := &cappedBuffer{max: config.maxSize}
+			if r.Body != nil && r.Body != http.NoBody {
+				r.Body = struct {
+					io.Reader
+					io.Closer
+				}{io.TeeReader(r.Body, reqBody), r.Body}
+			}
+			rw := &bodyResponseWriter{ResponseWriter: w, status: http.StatusOK, body: &cappedBuffer{max: config.maxSize}}
+
+			next.ServeHTTP(rw, r)
+
+			logger.Log(r.Context(), config.level, "http body",
+				StringAttr("method", r.Method),
+				IntAttr("status", rw.status),
+				StringAttr("request_body", config.redact(reqBody, r.Header.Get("Content-Type"))),
+				BoolAttr("request_body_truncated", reqBody.truncated),
+				StringAttr("response_body", config.redact(rw.body, rw.Header().Get("Content-Type"))),
+				BoolAttr("response_body_truncated", rw.body.truncated),
+			)
+		}
+		return http.HandlerFunc(fn)
+	}
+}
+
+// redact returns the captured body with the values of sensitive keys replaced.
+// Complete JSON documents are rewritten structurally and form-encoded bodies pair by
+// pair. Other JSON-looking bodies, such as one cut off by the size cap, are scanned for
+// sensitive keys, and a value that cannot be delimited masks the rest of the body.
+// Bodies of any other type are not logged, since their secrets cannot be located.
+func (c *bodyLogConfig) redact(b *cappedBuffer, contentType string) string {
+	data := b.buf.Bytes()
+	if len(data) == 0 {
+		return ""
+	}
+	if mediaType, _, _ := mime.ParseMediaType(contentType); mediaType == "application/x-www-form-urlencoded" {
+		return c.redactForm(string(data))
+	}
+	if !b.truncated {
+		var v any
+		if err := json.Unmarshal(data, &v); err == nil {
+			if out, err := json.Marshal(c.redactValue(v)); err == nil {
+				return string(out)
+			}
+		}
+	}
+	if trimmed := bytes.TrimSpace(data); len(trimmed) > 0 && (trimmed[0] == '{' || trimmed[0] == '[') {
+		return c.redactPartialJSON(data)
+	}
+	return redactedValue
+}
+
+// redactForm replaces the values of sensitive keys in a form-encoded body, keeping
+// the order and encoding of the other pairs.
+func (c *bodyLogConfig) redactForm(body string) string {
+	pairs := strings.Split(body, "&")
+	for i, pair := range pairs {
+		key, _, hasValue := strings.Cut(pair, "=")
+		name, err := url.QueryUnescape(key)
+		if err != nil {
+			name = key
+		}
+		if _, ok := c.fields[strings.ToLower(name)]; ok && hasValue {
+			pairs[i] = key + "=" + redactedValue
+		}
+	}
+	return strings.Join(pairs, "&")
+}
+
+// redactPartialJSON replaces the values of sensitive keys in a JSON document that
+// could not be decoded. Values of any kind are replaced whole; if a value runs to the
+// end of the data, everything from its start on is masked.
+func (c *bodyLogConfig) redactPartialJSON(data []byte) string {
+	var out strings.Builder
+	cursor := 0
+	for _, m := range jsonKeyPattern.FindAllSubmatchIndex(data, -1) {
+		if m[0] < cursor {
+			continue
+		}
+		if _, ok := c.fields[strings.ToLower(string(data[m[2]:m[3]]))]; !ok {
+			continue
+		}
+		out.Write(data[cursor:m[1]])
+		out.WriteString(`"` + redactedValue + `"`)
+		end, ok := jsonValueEnd(data, m[1])
+		if !ok {
+			return out.String()
+		}
+		cursor = end
+	}
+	out.Write(data[cursor:])
+	return out.String()
+}
+
+// jsonKeyPattern matches an object key with its colon, up to the start of the value.
+var jsonKeyPattern = regexp.MustCompile(`"((?:[^"\\]|\\.)*)"\s*:\s*`)
+
+// jsonValueEnd returns the offset just past the JSON value starting at i, and false if
+// the value is not complete within data.
+func jsonValueEnd(data []byte, i int) (int, bool) {
+	if i >= len(data) {
+		return len(data), false
+	}
+	switch data[i] {
+	case '"':
+		return jsonStringEnd(data, i)
+	case '{', '[':
+		depth := 0
+		for j := i; j < len(data); j++ {
+			switch data[j] {
+			case '"':
+				end, ok := jsonStringEnd(data, j)
+				if !ok {
+					return len(data), false
+				}
+				j = end - 1
+			case '{', '[':
+				depth++
+			case '}', ']':
+				depth--
+				if depth == 0 {
+					return j + 1, true
+				}
+			}
+		}
+		return len(data), false
+	default:
+		for j := i; j < len(data); j++ {
+			switch data[j] {
+			case ',', '}', ']', ' ', '\t', '\r', '\n':
+				return j, true
+			}
+		}
+		return len(data), false
+	}
+}
+
+// jsonStringEnd returns the offset just past the JSON string starting at i, and false
+// if the string is not terminated within data.
+func jsonStringEnd(data []byte, i int) (int, bool) {
+	for j := i + 1; j < len(data); j++ {
+		switch data[j] {
+		case '\\':
+			j++
+		case '"':
+			return j + 1, true
+		}
+	}
+	return len(data), false
+}
+
+// redactValue replaces the values of sensitive keys anywhere in a decoded JSON document.
+func (c *bodyLogConfig) redactValue(v any) any {
+	switch v := v.(type) {
+	case map[string]any:
+		for k, val := range v {
+			if _, ok := c.fields[strings.ToLower(k)]; ok {
+				v[k] = redactedValue
+				continue
+			}
+			v[k] = c.redactValue(val)
+		}
+	case []any:
+		for i, val := range v {
+			v[i] = c.redactValue(val)
+		}
+	}
+	return v
+}
+
+// cappedBuffer keeps the first max bytes written to it and discards the rest.
+type cappedBuffer struct {
+	buf       bytes.Buffer
+	max       int
+	truncated bool
+}
+
+// Write implements io.Writer, always reporting success so a tee never fails.
+func (b *cappedBuffer) Write(p []byte) (int, error) {
+	if room := b.max - b.buf.Len(); len(p) > room {
+		b.truncated = true
+		b.buf.Write(p[:max(room, 0)])
+		return len(p), nil
+	}
+	b.buf.Write(p)
+	return len(p), nil
+}
+
+// bodyResponseWriter captures the status code and response body.
+type bodyResponseWriter struct {
+	http.ResponseWriter
+	status      int
+	wroteHeader bool
+	body        *cappedBuffer
+}
+
+func (w *bodyResponseWriter) WriteHeader(code int) {
+	if !w.wroteHeader {
+		w.status = code
+		w.wroteHeader = true
+	}
+	w.ResponseWriter.WriteHeader(code)
+}
+
+func (w *bodyResponseWriter) Write(p []byte) (int, error) {
+	w.wroteHeader = true
+	n, err := w.ResponseWriter.Write(p)
+	w.body.Write(p[:n])
+	return n, err
+}
+
+// Unwrap lets http.ResponseController reach the underlying writer (Flush, Hijack, ...).
+func (w *bodyResponseWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
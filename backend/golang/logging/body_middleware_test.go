@@ -0,0 +1,143 @@
+package logging
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// serveBody runs a request with the given body through BodyLoggingMiddleware around a
+// handler that echoes the request body, and returns the echoed body and the logged record.
+func serveBody(t *testing.T, contentType, body string, opts ...BodyLogOption) (string, map[string]any) {
+	t.Helper()
+	var logged bytes.Buffer
+	logger := slog.New(slog.NewJSONHandler(&logged, &slog.HandlerOptions{Level: LevelDebug}))
+
+	handler := BodyLoggingMiddleware(opts...)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		data, err := io.ReadAll(r.Body)
+		if err != nil {
+			t.Errorf("read request body: %v", err)
+		}
+		w.Header().Set("Content-Type", contentType)
+		w.Write(data)
+	}))
+
+	req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(body))
+	req.Header.Set("Content-Type", contentType)
+	req = req.WithContext(ContextWithLogger(req.Context(), logger))
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+
+	var record map[string]any
+	if err := json.Unmarshal(logged.Bytes(), &record); err != nil {
+		t.Fatalf("decode log record %q: %v", logged.String(), err)
+	}
+	return rec.Body.String(), record
+}
+
+func TestBodyLoggingMiddlewareKeepsRequestBody(t *testing.T) {
+	body := `{"name":"alice","password":"hunter2"}`
+	echoed, record := serveBody(t, "application/json", body)
+
+	if echoed != body {
+		t.Errorf("handler read %q, want %q", echoed, body)
+	}
+	want := `{"name":"alice","password":"[REDACTED]"}`
+	if got := record["request_body"]; got != want {
+		t.Errorf("request_body = %v, want %s", got, want)
+	}
+	if got := record["response_body"]; got != want {
+		t.Errorf("response_body = %v, want %s", got, want)
+	}
+}
+
+func TestBodyLoggingMiddlewareSizeCap(t *testing.T) {
+	body := `{"name":"` + strings.Repeat("a", 100) + `"}`
+	echoed, record := serveBody(t, "application/json", body, WithBodyMaxSize(16))
+
+	if echoed != body {
+		t.Errorf("handler read %d bytes, want the full %d", len(echoed), len(body))
+	}
+	if got := record["request_body"]; got != body[:16] {
+		t.Errorf("request_body = %v, want %q", got, body[:16])
+	}
+	if got := record["request_body_truncated"]; got != true {
+		t.Errorf("request_body_truncated = %v, want true", got)
+	}
+}
+
+func TestBodyLoggingMiddlewareRedaction(t *testing.T) {
+	tests := []struct {
+		name        string
+		contentType string
+		body        string
+		maxSize     int
+		want        string
+	}{
+		{
+			name:        "nested",
+			contentType: "application/json",
+			body:        `{"user":{"name":"alice","Token":"abc"},"password":{"v":"x"}}`,
+			want:        `{"password":"[REDACTED]","user":{"Token":"[REDACTED]","name":"alice"}}`,
+		},
+		{
+			name:        "array",
+			contentType: "application/json",
+			body:        `[{"secret":["a","b"]},{"id":1}]`,
+			want:        `[{"secret":"[REDACTED]"},{"id":1}]`,
+		},
+		{
+			name:        "truncated object value",
+			contentType: "application/json",
+			body:        `{"password": {"v":"x"}, "name": "alice", "note": "` + strings.Repeat("n", 64) + `"}`,
+			maxSize:     60,
+			want:        `{"password": "[REDACTED]", "name": "alice", "note": "nnnnnnnnnn`,
+		},
+		{
+			name:        "truncated inside sensitive value",
+			contentType: "application/json",
+			body:        `{"name": "alice", "token": ["abc", "def"]}`,
+			maxSize:     30,
+			want:        `{"name": "alice", "token": "[REDACTED]"`,
+		},
+		{
+			name:        "truncated scalar value",
+			contentType: "application/json",
+			body:        `{"api_key": 1234567890}`,
+			maxSize:     16,
+			want:        `{"api_key": "[REDACTED]"`,
+		},
+		{
+			name:        "form",
+			contentType: "application/x-www-form-urlencoded",
+			body:        `user=alice&password=hunter2&Access%5Ftoken=t`,
+			want:        `user=alice&password=[REDACTED]&Access%5Ftoken=[REDACTED]`,
+		},
+		{
+			name:        "plain text",
+			contentType: "text/plain",
+			body:        `password: hunter2`,
+			want:        `[REDACTED]`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var opts []BodyLogOption
+			if tt.maxSize > 0 {
+				opts = append(opts, WithBodyMaxSize(tt.maxSize))
+			}
+			_, record := serveBody(t, tt.contentType, tt.body, opts...)
+			if got := record["request_body"]; got != tt.want {
+				t.Errorf("request_body = %v, want %s", got, tt.want)
+			}
+			if got := record["response_body"]; got != tt.want {
+				t.Errorf("response_body = %v, want %s", got, tt.want)
+			}
+		})
+	}
+}
@@ -4,6 +4,10 @@ go 1.24.1
 
 require (
 	github.com/RRWM1rr0rB/faraway_lib/backend/golang/tracing v0.0.0-20250331145437-1c4c07eac7c2
+	go.opentelemetry.io/contrib/bridges/otelslog v0.10.0
+	go.opentelemetry.io/otel/exporters/otlp/otlplog/otlploghttp v0.11.0
+	go.opentelemetry.io/otel/log v0.11.0
+	go.opentelemetry.io/otel/sdk/log v0.11.0
 	go.opentelemetry.io/otel/trace v1.35.0
 	google.golang.org/grpc v1.71.0
 )
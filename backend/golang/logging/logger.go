@@ -4,6 +4,7 @@ import (
 	"context"
 	"log/slog"
 	"os"
+	"time"
 )
 
 // Default logger configuration constants.
@@ -36,6 +37,18 @@ func NewLogger(opts ...LoggerOption) *Logger {
 	if config.IsJSON {
 		h = NewJSONHandler(os.Stdout, options)
 	}
+	if config.OTelEndpoint != "" {
+		otelHandler, err := NewOTelHandler(config.OTelEndpoint)
+		if err != nil {
+			// Keep logging locally rather than failing logger construction
+			New(h).Error("failed to enable OpenTelemetry log export", "error", err)
+		} else {
+			h = NewMultiHandler(h, &levelHandler{Handler: otelHandler, level: config.Level})
+		}
+	}
+	if config.ErrorRateLimit > 0 && config.ErrorRateWindow > 0 {
+		h = newErrorRateLimitHandler(h, config.ErrorRateLimit, config.ErrorRateWindow)
+	}
 
 	logger := New(h)
 	if config.SetDefault {
@@ -51,6 +64,11 @@ type LoggerOptions struct {
 	AddSource  bool
 	IsJSON     bool
 	SetDefault bool
+
+	ErrorRateLimit  int
+	ErrorRateWindow time.Duration
+
+	OTelEndpoint string
 }
 
 // LoggerOption functional options pattern for logger configuration.
@@ -95,6 +113,27 @@ func WithSetDefault(setDefault bool) LoggerOption {
 	}
 }
 
+// WithErrorRateLimit collapses identical error logs, fingerprinted by message template:
+// at most n occurrences are written per window, and the first one after a window
+// with drops carries the number of suppressed records. If the error stops recurring,
+// the count is reported by a summary record along with the next log record once
+// the window ended. n <= 0 disables the limit.
+func WithErrorRateLimit(n int, window time.Duration) LoggerOption {
+	return func(o *LoggerOptions) {
+		o.ErrorRateLimit = n
+		o.ErrorRateWindow = window
+	}
+}
+
+// WithOTelExport additionally exports logs as OpenTelemetry logs over OTLP/HTTP to endpoint
+// (host:port), so they land next to traces with their trace and span IDs attached.
+// Stdout output is kept. Call Shutdown before exit to flush pending records.
+func WithOTelExport(endpoint string) LoggerOption {
+	return func(o *LoggerOptions) {
+		o.OTelEndpoint = endpoint
+	}
+}
+
 // WithAttrs adds attributes to the logger in the context.
 func WithAttrs(ctx context.Context, attrs ...Attr) *Logger {
 	logger := L(ctx)
@@ -4,6 +4,8 @@ import (
 	"context"
 	"log/slog"
 	"testing"
+
+	sdk_log "go.opentelemetry.io/otel/sdk/log"
 )
 
 func TestNewLogger(t *testing.T) {
@@ -26,3 +28,27 @@ func TestNewLogger(t *testing.T) {
 	newL.Warn("warn")
 	newL.Error("error")
 }
+
+func TestOTelHandlersShareProvider(t *testing.T) {
+	defer Shutdown(context.Background())
+	const endpoint = "127.0.0.1:4318"
+
+	provider := func() *sdk_log.LoggerProvider {
+		otelMu.Lock()
+		defer otelMu.Unlock()
+		return otelProviders[endpoint]
+	}
+
+	if _, err := NewOTelHandler(endpoint); err != nil {
+		t.Fatal(err)
+	}
+	first := provider()
+	if _, err := NewOTelHandler(endpoint); err != nil {
+		t.Fatal(err)
+	}
+
+	// The first handler's provider is reused, not replaced and shut down
+	if first == nil || provider() != first {
+		t.Fatal("second OTel handler for the same endpoint replaced the provider")
+	}
+}
@@ -0,0 +1,64 @@
+package logging
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+)
+
+// MultiHandler fans out each record to several handlers,
+// e.g. JSON to stdout plus error-only records to a remote sink.
+type MultiHandler struct {
+	handlers []Handler
+}
+
+// NewMultiHandler creates a handler that forwards records to all given handlers.
+func NewMultiHandler(handlers ...Handler) *MultiHandler {
+	return &MultiHandler{handlers: handlers}
+}
+
+// Enabled reports whether any of the handlers is enabled for the level.
+func (m *MultiHandler) Enabled(ctx context.Context, level Level) bool {
+	for _, h := range m.handlers {
+		if h.Enabled(ctx, level) {
+			return true
+		}
+	}
+	return false
+}
+
+// Handle passes the record to every handler enabled for its level.
+// Errors from individual handlers are joined; a failing handler doesn't stop the others.
+func (m *MultiHandler) Handle(ctx context.Context, r slog.Record) error {
+	var errs []error
+	for _, h := range m.handlers {
+		if !h.Enabled(ctx, r.Level) {
+			continue
+		}
+		if err := h.Handle(ctx, r.Clone()); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// WithAttrs returns a MultiHandler whose handlers all have the attributes added.
+func (m *MultiHandler) WithAttrs(attrs []Attr) Handler {
+	handlers := make([]Handler, len(m.handlers))
+	for i, h := range m.handlers {
+		handlers[i] = h.WithAttrs(attrs)
+	}
+	return &MultiHandler{handlers: handlers}
+}
+
+// WithGroup returns a MultiHandler whose handlers all start the group.
+func (m *MultiHandler) WithGroup(name string) Handler {
+	if name == "" {
+		return m
+	}
+	handlers := make([]Handler, len(m.handlers))
+	for i, h := range m.handlers {
+		handlers[i] = h.WithGroup(name)
+	}
+	return &MultiHandler{handlers: handlers}
+}
@@ -0,0 +1,102 @@
+package logging
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sync"
+
+	"go.opentelemetry.io/contrib/bridges/otelslog"
+	"go.opentelemetry.io/otel/exporters/otlp/otlplog/otlploghttp"
+	"go.opentelemetry.io/otel/log/global"
+	sdk_log "go.opentelemetry.io/otel/sdk/log"
+)
+
+// otelScope is the instrumentation scope name attached to exported log records.
+const otelScope = "github.com/RRWM1rr0rB/faraway_lib/backend/golang/logging"
+
+var (
+	ErrNewLogExporter = errors.New("failed to create OTLP log exporter")
+
+	otelMu        sync.Mutex
+	otelProviders = make(map[string]*sdk_log.LoggerProvider) // Endpoint -> provider shared by its handlers
+)
+
+// NewOTelHandler creates a handler that exports records as OpenTelemetry logs over OTLP/HTTP
+// to endpoint (host:port). Records logged with a context carrying a span get its trace and span IDs.
+// Handlers for the same endpoint share one logger provider, so building several loggers with
+// export enabled keeps all of them exporting. The first provider created is also registered
+// globally; call Shutdown to flush all of them before exit.
+func NewOTelHandler(endpoint string) (Handler, error) {
+	otelMu.Lock()
+	defer otelMu.Unlock()
+
+	provider, ok := otelProviders[endpoint]
+	if !ok {
+		exporter, err := otlploghttp.New(context.Background(),
+			otlploghttp.WithEndpoint(endpoint),
+			otlploghttp.WithInsecure(),
+		)
+		if err != nil {
+			return nil, errors.Join(ErrNewLogExporter, err)
+		}
+
+		provider = sdk_log.NewLoggerProvider(
+			sdk_log.WithProcessor(sdk_log.NewBatchProcessor(exporter)),
+		)
+		if len(otelProviders) == 0 {
+			global.SetLoggerProvider(provider)
+		}
+		otelProviders[endpoint] = provider
+	}
+
+	return otelslog.NewHandler(otelScope, otelslog.WithLoggerProvider(provider)), nil
+}
+
+// levelHandler drops records below a minimum level before they reach the wrapped handler.
+type levelHandler struct {
+	Handler
+	level slog.Leveler
+}
+
+// Enabled implements Handler.
+func (h *levelHandler) Enabled(ctx context.Context, level Level) bool {
+	return level >= h.level.Level() && h.Handler.Enabled(ctx, level)
+}
+
+// Handle implements Handler.
+func (h *levelHandler) Handle(ctx context.Context, r slog.Record) error {
+	if r.Level < h.level.Level() {
+		return nil
+	}
+	return h.Handler.Handle(ctx, r)
+}
+
+// WithAttrs implements Handler.
+func (h *levelHandler) WithAttrs(attrs []Attr) Handler {
+	return &levelHandler{Handler: h.Handler.WithAttrs(attrs), level: h.level}
+}
+
+// WithGroup implements Handler.
+func (h *levelHandler) WithGroup(name string) Handler {
+	return &levelHandler{Handler: h.Handler.WithGroup(name), level: h.level}
+}
+
+// Shutdown flushes and stops every OpenTelemetry log export started by NewOTelHandler
+// or WithOTelExport; handlers created before stop exporting. It is a no-op if export
+// was never enabled.
+func Shutdown(ctx context.Context) error {
+	otelMu.Lock()
+	providers := otelProviders
+	otelProviders = make(map[string]*sdk_log.LoggerProvider)
+	otelMu.Unlock()
+
+	var errs []error
+	for endpoint, provider := range providers {
+		if err := provider.Shutdown(ctx); err != nil {
+			errs = append(errs, fmt.Errorf("shutdown log export to %s: %w", endpoint, err))
+		}
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,163 @@
+package logging
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+	"time"
+)
+
+// suppressedLogKey holds the number of identical records dropped in the previous window.
+const suppressedLogKey = "suppressed"
+
+// errorRateLimitHandler collapses storms of identical error records.
+// Records are fingerprinted by level and message template, not by attributes,
+// so "db query failed" with different query IDs still counts as one error.
+type errorRateLimitHandler struct {
+	next  Handler
+	state *rateLimitState
+}
+
+type rateLimitState struct {
+	mu     sync.Mutex
+	limit  int
+	window time.Duration
+	seen   map[rateLimitKey]*rateLimitEntry
+	// Fingerprints with dropped records not yet reported
+	pending map[rateLimitKey]*rateLimitEntry
+}
+
+type rateLimitKey struct {
+	level   Level
+	message string
+}
+
+type rateLimitEntry struct {
+	windowStart time.Time
+	count       int
+	suppressed  int
+}
+
+// newErrorRateLimitHandler passes at most limit identical error records per window to next.
+func newErrorRateLimitHandler(next Handler, limit int, window time.Duration) *errorRateLimitHandler {
+	return &errorRateLimitHandler{
+		next: next,
+		state: &rateLimitState{
+			limit:   limit,
+			window:  window,
+			seen:    make(map[rateLimitKey]*rateLimitEntry),
+			pending: make(map[rateLimitKey]*rateLimitEntry),
+		},
+	}
+}
+
+// Enabled implements Handler.
+func (h *errorRateLimitHandler) Enabled(ctx context.Context, level Level) bool {
+	return h.next.Enabled(ctx, level)
+}
+
+// Handle implements Handler. The first record after a window with drops
+// carries the number of dropped records in the "suppressed" attribute.
+// Drops of storms that ended are reported by a summary record with the storm's
+// message and the "suppressed" count, emitted along with any later record.
+func (h *errorRateLimitHandler) Handle(ctx context.Context, r slog.Record) error {
+	var err error
+	if r.Level < LevelError {
+		err = h.next.Handle(ctx, r)
+	} else if pass, suppressed := h.state.allow(rateLimitKey{level: r.Level, message: r.Message}, r.Time); pass {
+		if suppressed > 0 {
+			r = r.Clone()
+			r.AddAttrs(slog.Int(suppressedLogKey, suppressed))
+		}
+		err = h.next.Handle(ctx, r)
+	}
+
+	for _, summary := range h.state.expired(r.Time) {
+		if serr := h.next.Handle(ctx, summary); serr != nil && err == nil {
+			err = serr
+		}
+	}
+	return err
+}
+
+// WithAttrs implements Handler, sharing the rate limit state.
+func (h *errorRateLimitHandler) WithAttrs(attrs []Attr) Handler {
+	return &errorRateLimitHandler{next: h.next.WithAttrs(attrs), state: h.state}
+}
+
+// WithGroup implements Handler, sharing the rate limit state.
+func (h *errorRateLimitHandler) WithGroup(name string) Handler {
+	return &errorRateLimitHandler{next: h.next.WithGroup(name), state: h.state}
+}
+
+// allow reports whether a record may be logged and how many were dropped before it.
+func (s *rateLimitState) allow(key rateLimitKey, now time.Time) (bool, int) {
+	if now.IsZero() {
+		now = time.Now()
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	e, ok := s.seen[key]
+	if !ok {
+		s.sweep(now)
+		e = &rateLimitEntry{windowStart: now}
+		s.seen[key] = e
+	}
+
+	var suppressed int
+	if now.Sub(e.windowStart) >= s.window {
+		suppressed = e.suppressed
+		e.windowStart = now
+		e.count = 0
+		e.suppressed = 0
+		delete(s.pending, key)
+	}
+
+	e.count++
+	if e.count > s.limit {
+		e.suppressed++
+		s.pending[key] = e
+		return false, 0
+	}
+	return true, suppressed
+}
+
+// expired returns summary records for fingerprints whose window with drops ended
+// by now without a record of their own reporting the drops, and clears those counts.
+func (s *rateLimitState) expired(now time.Time) []slog.Record {
+	if now.IsZero() {
+		now = time.Now()
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var summaries []slog.Record
+	for key, e := range s.pending {
+		if now.Sub(e.windowStart) < s.window {
+			continue
+		}
+		r := slog.NewRecord(now, key.level, key.message, 0)
+		r.AddAttrs(slog.Int(suppressedLogKey, e.suppressed))
+		summaries = append(summaries, r)
+		e.suppressed = 0
+		delete(s.pending, key)
+	}
+	return summaries
+}
+
+// sweep drops fingerprints idle for more than a window with nothing pending,
+// assumes lock is already held.
+func (s *rateLimitState) sweep(now time.Time) {
+	const sweepThreshold = 1024
+	if len(s.seen) < sweepThreshold {
+		return
+	}
+	for k, e := range s.seen {
+		if e.suppressed == 0 && now.Sub(e.windowStart) >= s.window {
+			delete(s.seen, k)
+		}
+	}
+}
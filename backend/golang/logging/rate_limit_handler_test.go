@@ -0,0 +1,115 @@
+package logging
+
+import (
+	"context"
+	"log/slog"
+	"strconv"
+	"testing"
+	"time"
+)
+
+// recordingHandler keeps the message and "suppressed" count of every record it handles.
+type recordingHandler struct {
+	records []recorded
+}
+
+type recorded struct {
+	message    string
+	suppressed int64 // -1 if the attribute is missing
+}
+
+func (h *recordingHandler) Enabled(context.Context, Level) bool { return true }
+func (h *recordingHandler) WithAttrs([]Attr) Handler            { return h }
+func (h *recordingHandler) WithGroup(string) Handler            { return h }
+
+func (h *recordingHandler) Handle(_ context.Context, r slog.Record) error {
+	rec := recorded{message: r.Message, suppressed: -1}
+	r.Attrs(func(a Attr) bool {
+		if a.Key == suppressedLogKey {
+			rec.suppressed = a.Value.Int64()
+		}
+		return true
+	})
+	h.records = append(h.records, rec)
+	return nil
+}
+
+func TestErrorRateLimitHandler(t *testing.T) {
+	next := &recordingHandler{}
+	h := newErrorRateLimitHandler(next, 2, time.Minute)
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	ctx := context.Background()
+
+	handle := func(level Level, msg string, at time.Duration) {
+		if err := h.Handle(ctx, slog.NewRecord(start.Add(at), level, msg, 0)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	expect := func(want ...recorded) {
+		t.Helper()
+		if len(next.records) != len(want) {
+			t.Fatalf("got %+v, want %+v", next.records, want)
+		}
+		for i := range want {
+			if next.records[i] != want[i] {
+				t.Fatalf("got %+v, want %+v", next.records, want)
+			}
+		}
+		next.records = nil
+	}
+
+	// First pass: up to the limit per window, then drops
+	for i := range 5 {
+		handle(LevelError, "db down", time.Duration(i)*time.Second)
+	}
+	expect(recorded{"db down", -1}, recorded{"db down", -1})
+
+	// Lower levels are never limited
+	for range 3 {
+		handle(LevelInfo, "request", 10*time.Second)
+	}
+	expect(recorded{"request", -1}, recorded{"request", -1}, recorded{"request", -1})
+
+	// Count carry: the next occurrence after the window reports the drops
+	handle(LevelError, "db down", time.Minute)
+	expect(recorded{"db down", 3})
+
+	// A storm that stops is reported by a summary with the next record of any kind
+	for i := range 4 {
+		handle(LevelError, "cache miss", time.Minute+time.Duration(i)*time.Second)
+	}
+	expect(recorded{"cache miss", -1}, recorded{"cache miss", -1})
+	handle(LevelInfo, "request", time.Minute+30*time.Second)
+	expect(recorded{"request", -1})
+	handle(LevelInfo, "request", 2*time.Minute+5*time.Second)
+	expect(recorded{"request", -1}, recorded{"cache miss", 2})
+
+	// Reported once: neither another record nor the storm's return repeat the count
+	handle(LevelInfo, "request", 3*time.Minute)
+	handle(LevelError, "cache miss", 4*time.Minute)
+	expect(recorded{"request", -1}, recorded{"cache miss", -1})
+}
+
+func TestErrorRateLimitSweepEvictsReportedStorms(t *testing.T) {
+	h := newErrorRateLimitHandler(&recordingHandler{}, 1, time.Minute)
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	ctx := context.Background()
+
+	for i := range 1100 {
+		msg := "error " + strconv.Itoa(i)
+		h.Handle(ctx, slog.NewRecord(start, LevelError, msg, 0))
+		h.Handle(ctx, slog.NewRecord(start, LevelError, msg, 0)) // Dropped
+	}
+	// After the window the summaries go out, so the fingerprints can be swept
+	h.Handle(ctx, slog.NewRecord(start.Add(2*time.Minute), LevelError, "new", 0))
+
+	h.state.mu.Lock()
+	defer h.state.mu.Unlock()
+	if n := len(h.state.pending); n != 0 {
+		t.Fatalf("%d drop counts still pending", n)
+	}
+	h.state.sweep(start.Add(2 * time.Minute))
+	if n := len(h.state.seen); n > 1 {
+		t.Fatalf("%d fingerprints left after sweep, want only the live one", n)
+	}
+}
@@ -6,6 +6,7 @@ import (
 	"encoding/json"
 	"path"
 	"reflect"
+	"sync/atomic"
 
 	"github.com/iancoleman/strcase"
 	"go.opentelemetry.io/otel/attribute"
@@ -73,6 +74,30 @@ func AttributesFrom(prefix string, obj interface{}) []attribute.KeyValue {
 
 // --- Helpers ---
 
+// contextAttrs holds the context keys configured via WithContextAttributes.
+var contextAttrs atomic.Pointer[[]contextAttr]
+
+// traceContextValues attaches the configured context values to the span.
+func traceContextValues(ctx context.Context, span trace.Span) {
+	attrs := contextAttrs.Load()
+	if attrs == nil || len(*attrs) == 0 || !span.IsRecording() {
+		return
+	}
+
+	for _, attr := range *attrs {
+		val := ctx.Value(attr.key)
+		if val == nil {
+			continue
+		}
+		if av, ok := attributeValue(reflect.ValueOf(val)); ok {
+			span.SetAttributes(attribute.KeyValue{
+				Key:   attribute.Key(attr.name),
+				Value: av,
+			})
+		}
+	}
+}
+
 const (
 	tagName = "trace"
 	dot     = '.'
@@ -0,0 +1,54 @@
+package tracing
+
+import (
+	"context"
+	"testing"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	sdk_trace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+type requestIDKey struct{}
+
+type tenantIDKey struct{}
+
+func TestStartAddsContextAttributes(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	provider := sdk_trace.NewTracerProvider(sdk_trace.WithSpanProcessor(recorder))
+	previous := otel.GetTracerProvider()
+	otel.SetTracerProvider(provider)
+	defer otel.SetTracerProvider(previous)
+
+	cfg := &config{}
+	WithContextAttributes(map[string]any{
+		"request.id": requestIDKey{},
+		"tenant.id":  tenantIDKey{},
+	})(cfg)
+	contextAttrs.Store(&cfg.contextAttrs)
+	defer contextAttrs.Store(nil)
+
+	ctx := context.WithValue(context.Background(), requestIDKey{}, "req-42")
+	ctx = context.WithValue(ctx, tenantIDKey{}, 7)
+	_, span := Start(ctx, "op")
+	span.End()
+
+	spans := recorder.Ended()
+	if len(spans) != 1 {
+		t.Fatalf("got %d spans, want 1", len(spans))
+	}
+	got := make(map[attribute.Key]attribute.Value)
+	for _, kv := range spans[0].Attributes() {
+		got[kv.Key] = kv.Value
+	}
+	if v := got["request.id"]; v.AsString() != "req-42" {
+		t.Errorf("request.id = %v, want req-42", v.Emit())
+	}
+	if v := got["tenant.id"]; v.AsInt64() != 7 {
+		t.Errorf("tenant.id = %v, want 7", v.Emit())
+	}
+	if len(got) != 2 {
+		t.Errorf("got attributes %v, want only the configured ones", got)
+	}
+}
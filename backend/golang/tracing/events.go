@@ -0,0 +1,44 @@
+package tracing
+
+import (
+	"context"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// durationAttrKey holds the duration of a timed event in milliseconds.
+const durationAttrKey = "duration_ms"
+
+// AddEvent records a named event with optional attributes on the current span.
+func AddEvent(ctx context.Context, name string, attrs ...attribute.KeyValue) {
+	span := trace.SpanFromContext(ctx)
+	if !span.IsRecording() {
+		return
+	}
+	span.AddEvent(name, trace.WithAttributes(attrs...))
+}
+
+// TimedEvent records a "<name>.start" event on the current span and returns a function
+// that records "<name>.end" with the elapsed time, for marking a sub-operation without
+// starting a child span:
+//
+//	defer tracing.TimedEvent(ctx, "cache.lookup")()
+func TimedEvent(ctx context.Context, name string) func() {
+	span := trace.SpanFromContext(ctx)
+	if !span.IsRecording() {
+		return func() {}
+	}
+
+	start := time.Now()
+	span.AddEvent(name+".start", trace.WithTimestamp(start))
+	return func() {
+		end := time.Now()
+		elapsed := end.Sub(start)
+		span.AddEvent(name+".end",
+			trace.WithTimestamp(end),
+			trace.WithAttributes(attribute.Float64(durationAttrKey, float64(elapsed)/float64(time.Millisecond))),
+		)
+	}
+}
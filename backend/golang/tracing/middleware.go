@@ -7,6 +7,7 @@ import (
 	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
 	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
 	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/trace"
 	"google.golang.org/grpc"
 )
 
@@ -48,7 +49,10 @@ func Middleware(next http.Handler) http.Handler {
 
 		// Create new handler
 		newHandler := otelhttp.NewHandler(
-			next,
+			http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				traceContextValues(r.Context(), trace.SpanFromContext(r.Context()))
+				next.ServeHTTP(w, r)
+			}),
 			pathKey,
 			otelhttp.WithPropagators(otel.GetTextMapPropagator()),
 		)
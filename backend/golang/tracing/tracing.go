@@ -58,6 +58,8 @@ func New(params ...ConfigParam) (trace.TracerProvider, error) {
 		sdk_trace.WithResource(res),
 	)
 
+	contextAttrs.Store(&cfg.contextAttrs)
+
 	otel.SetTracerProvider(provider)
 	otel.SetTextMapPropagator(propagation.TraceContext{})
 
@@ -66,7 +68,9 @@ func New(params ...ConfigParam) (trace.TracerProvider, error) {
 
 // Start creates a new span.
 func Start(ctx context.Context, name string, opts ...trace.SpanStartOption) (context.Context, trace.Span) {
-	return otel.Tracer("").Start(ctx, name, opts...)
+	ctx, span := otel.Tracer("").Start(ctx, name, opts...)
+	traceContextValues(ctx, span)
+	return ctx, span
 }
 
 // Continue creates a new span that continues the given span.
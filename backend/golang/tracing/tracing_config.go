@@ -2,6 +2,8 @@ package tracing
 
 import (
 	"errors"
+	"slices"
+	"strings"
 )
 
 var (
@@ -22,6 +24,13 @@ type config struct {
 	serviceName    string
 	serviceVersion string
 	envName        string
+	contextAttrs   []contextAttr
+}
+
+// contextAttr names the span attribute carrying the value of a context key.
+type contextAttr struct {
+	name string
+	key  any
 }
 
 // Validate checks required fields.
@@ -67,3 +76,17 @@ func WithServiceVersion(version string) ConfigParam {
 func WithEnvName(env string) ConfigParam {
 	return func(c *config) { c.envName = env }
 }
+
+// WithContextAttributes sets context values attached as attributes to every span
+// created via Start, Continue and Middleware, mapping attribute names to context keys,
+// e.g. {"request.id": requestIDKey{}}. Context keys are usually unexported types,
+// so the names can't be derived from them.
+func WithContextAttributes(attrs map[string]any) ConfigParam {
+	return func(c *config) {
+		for name, key := range attrs {
+			c.contextAttrs = append(c.contextAttrs, contextAttr{name: name, key: key})
+		}
+		// Map order is random, keep attributes in a stable order
+		slices.SortFunc(c.contextAttrs, func(a, b contextAttr) int { return strings.Compare(a.name, b.name) })
+	}
+}